@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/store"
+)
+
+func activitiesListMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	afterFlag := flags.Int("afterId", 0, "beginning activity id, exclusive")
+	storeFlags := addStoreFlags(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *storeFlags.cacheDir); err != nil {
+		return err
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	summaries, err := source.GetActivitySummaries(model.ActivityId(*afterFlag))
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	return outputFlags.print(summaryTable(summaries))
+}
+
+func activitiesGetMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	storeFlags := addStoreFlags(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return usageErrorf("an activity id is required")
+	}
+	activityId, err := strconv.ParseUint(flags.Arg(0), 10, 64)
+	if err != nil {
+		return usageErrorf("invalid activity id %s", flags.Arg(0))
+	}
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *storeFlags.cacheDir); err != nil {
+		return err
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	activities, err := source.GetActivities([]model.ActivityId{model.ActivityId(activityId)})
+	if err != nil {
+		return fmt.Errorf("getting activity: %s", err)
+	}
+	if len(activities) == 0 {
+		return fmt.Errorf("activity %d not found", activityId)
+	}
+
+	return outputFlags.print(activityTable(activities))
+}
+
+func getActivities(source store.Source, summaries []*model.ActivitySummary) ([]*model.Activity, error) {
+	activityIds := make([]model.ActivityId, len(summaries))
+	for i, summary := range summaries {
+		activityIds[i] = summary.Id
+	}
+
+	return source.GetActivities(activityIds)
+}
+
+func summaryTable(summaries []*model.ActivitySummary) *table {
+	t := newTable("id", "name", "distance", "average_speed", "total_elevation_gain", "moving_time", "elapsed_time", "start_date")
+	for _, summary := range summaries {
+		t.add(summary.Id, summary.Name, distance(summary.Distance), speedOrPace(summary.Type, summary.AverageSpeed),
+			elevation(summary.TotalElevationGain), duration(summary.MovingTime), duration(summary.ElapsedTime), summary.StartDate)
+	}
+	return t
+}
+
+func activityTable(activities []*model.Activity) *table {
+	t := newTable("id", "name", "type", "distance", "average_speed", "max_speed", "total_elevation_gain", "moving_time",
+		"elapsed_time", "start_date", "start_date_local", "gear_id", "kudos_count", "comment_count", "lap_count", "segment_effort_count")
+	for _, activity := range activities {
+		t.add(activity.Id, activity.Name, activity.Type, distance(activity.Distance), speedOrPace(activity.Type, activity.AverageSpeed),
+			speedOrPace(activity.Type, activity.MaxSpeed), elevation(activity.TotalElevationGain), duration(activity.MovingTime),
+			duration(activity.ElapsedTime), activity.StartDate, activity.StartDateLocal, activity.GearId, activity.KudosCount,
+			activity.CommentCount, len(activity.Laps), len(activity.SegmentEfforts))
+	}
+	return t
+}
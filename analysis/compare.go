@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// Efforts on the same segment in two activities.
+type EffortComparison struct {
+	Segment *model.Segment
+	A       *model.SegmentEffort
+	B       *model.SegmentEffort
+}
+
+// Seconds B took longer than A; negative if B was faster.
+func (c *EffortComparison) Delta() int64 {
+	return int64(c.B.ElapsedTime) - int64(c.A.ElapsedTime)
+}
+
+// Delta as a percentage of A's time.
+func (c *EffortComparison) Change() float64 {
+	if c.A.ElapsedTime == 0 {
+		return 0
+	}
+	return float64(c.Delta()) / float64(c.A.ElapsedTime) * 100
+}
+
+// Join the segment efforts of two activities by segment, in the order of A's efforts.
+// A segment ridden several times in each activity pairs the first efforts, then the second,
+// and so on. Segments only in one of the activities are left out.
+func CompareEfforts(a *model.Activity, b *model.Activity) []*EffortComparison {
+	bEfforts := make(map[model.SegmentId][]*model.SegmentEffort)
+	for _, effort := range b.SegmentEfforts {
+		bEfforts[effort.Segment.Id] = append(bEfforts[effort.Segment.Id], effort)
+	}
+
+	comparisons := make([]*EffortComparison, 0)
+	for _, effort := range a.SegmentEfforts {
+		matches := bEfforts[effort.Segment.Id]
+		if len(matches) == 0 {
+			continue
+		}
+		comparisons = append(comparisons, &EffortComparison{Segment: effort.Segment, A: effort, B: matches[0]})
+		bEfforts[effort.Segment.Id] = matches[1:]
+	}
+	return comparisons
+}
+
+// Elapsed times of two activities at the same distance.
+type DistanceComparison struct {
+	Distance   float64 // Meters
+	TimeA      float64 // Seconds
+	TimeB      float64 // Seconds
+	Delta      float64 // Seconds B was behind A at the distance; negative if ahead
+	SplitDelta float64 // Change in Delta since the previous distance: time lost, or gained if negative
+}
+
+// Compare two activities over the same route by the time each took to reach every interval
+// of distance, interpolating between samples, up to the shorter of the two distances.
+// Returns nothing if either activity lacks time and distance streams.
+func CompareStreams(a *model.Streams, b *model.Streams, interval float64) []*DistanceComparison {
+	comparisons := make([]*DistanceComparison, 0)
+	if interval <= 0 || !hasDistance(a) || !hasDistance(b) {
+		return comparisons
+	}
+
+	total := float64(a.Distance[len(a.Distance)-1])
+	if bTotal := float64(b.Distance[len(b.Distance)-1]); bTotal < total {
+		total = bTotal
+	}
+
+	previous := 0.0
+	for i := 1; float64(i)*interval <= total; i++ {
+		distance := float64(i) * interval
+		comparison := &DistanceComparison{Distance: distance, TimeA: timeAt(a, distance), TimeB: timeAt(b, distance)}
+		comparison.Delta = comparison.TimeB - comparison.TimeA
+		comparison.SplitDelta = comparison.Delta - previous
+		previous = comparison.Delta
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons
+}
+
+func hasDistance(streams *model.Streams) bool {
+	return len(streams.Distance) > 0 && len(streams.Distance) == len(streams.Time)
+}
+
+// Seconds since the start when the distance was first reached, interpolated between samples.
+func timeAt(streams *model.Streams, distance float64) float64 {
+	i := sort.Search(len(streams.Distance), func(i int) bool { return float64(streams.Distance[i]) >= distance })
+	if i == len(streams.Distance) {
+		return float64(streams.Time[len(streams.Time)-1])
+	}
+	if i == 0 {
+		return float64(streams.Time[0])
+	}
+
+	d0, d1 := float64(streams.Distance[i-1]), float64(streams.Distance[i])
+	t0, t1 := float64(streams.Time[i-1]), float64(streams.Time[i])
+	return t0 + (distance-d0)/(d1-d0)*(t1-t0)
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestCompareEfforts(t *testing.T) {
+	hawkHill := &model.Segment{Id: 1, Name: "Hawk Hill"}
+	descent := &model.Segment{Id: 2, Name: "Descent"}
+	onlyA := &model.Segment{Id: 3, Name: "Detour"}
+
+	a := &model.Activity{SegmentEfforts: []*model.SegmentEffort{
+		{Id: 11, Segment: hawkHill, ElapsedTime: 600},
+		{Id: 12, Segment: onlyA, ElapsedTime: 100},
+		{Id: 13, Segment: descent, ElapsedTime: 200},
+		{Id: 14, Segment: hawkHill, ElapsedTime: 650},
+	}}
+	b := &model.Activity{SegmentEfforts: []*model.SegmentEffort{
+		{Id: 21, Segment: hawkHill, ElapsedTime: 570},
+		{Id: 22, Segment: descent, ElapsedTime: 210},
+		{Id: 23, Segment: hawkHill, ElapsedTime: 650},
+	}}
+
+	comparisons := CompareEfforts(a, b)
+
+	expected := []struct {
+		a, b   model.SegmentEffortId
+		delta  int64
+		change float64
+	}{{11, 21, -30, -5}, {13, 22, 10, 5}, {14, 23, 0, 0}}
+	if len(comparisons) != len(expected) {
+		t.Fatalf("Comparison count was not as expected. expected=%d, actual=%d", len(expected), len(comparisons))
+	}
+	for i, e := range expected {
+		c := comparisons[i]
+		if c.A.Id != e.a || c.B.Id != e.b || c.Delta() != e.delta || c.Change() != e.change {
+			t.Fatalf("Comparison was not as expected. expected=%v, actual=%d %d %d %f", e, c.A.Id, c.B.Id, c.Delta(), c.Change())
+		}
+	}
+}
+
+func TestCompareStreams(t *testing.T) {
+	// A goes a steady 10 m/s. B starts at 5 m/s, then goes 20 m/s after 1000m.
+	a := &model.Streams{Time: []uint32{0, 100, 200, 300}, Distance: []float32{0, 1000, 2000, 3000}}
+	b := &model.Streams{Time: []uint32{0, 200, 250, 300}, Distance: []float32{0, 1000, 2000, 2500}}
+
+	comparisons := CompareStreams(a, b, 1000)
+
+	expected := []*DistanceComparison{
+		{Distance: 1000, TimeA: 100, TimeB: 200, Delta: 100, SplitDelta: 100},
+		{Distance: 2000, TimeA: 200, TimeB: 250, Delta: 50, SplitDelta: -50},
+	}
+	if len(comparisons) != len(expected) {
+		t.Fatalf("Comparison count was not as expected. expected=%d, actual=%d", len(expected), len(comparisons))
+	}
+	for i, e := range expected {
+		if *comparisons[i] != *e {
+			t.Fatalf("Comparison was not as expected. expected=%+v, actual=%+v", e, comparisons[i])
+		}
+	}
+
+	if actual := timeAt(b, 1500); math.Abs(actual-225) > 1e-9 {
+		t.Fatalf("Interpolated time was not as expected. expected=225, actual=%f", actual)
+	}
+	if comparisons := CompareStreams(a, &model.Streams{}, 1000); len(comparisons) != 0 {
+		t.Fatalf("Expected no comparisons without distance but got %v", comparisons)
+	}
+}
@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// Durations best efforts are usually compared over: 5 seconds, 1, 5, 20 and 60 minutes
+var StandardDurations = []uint32{5, 60, 5 * 60, 20 * 60, 60 * 60}
+
+// The best average of a stream over a duration, and the activity it was set in.
+type Best struct {
+	Duration   uint32  // Seconds
+	Value      float64 // Average over the duration: watts, or meters/sec for pace
+	ActivityId model.ActivityId
+	Date       time.Time // Local date of the activity
+}
+
+// The mean-maximal power of an activity for each duration. Durations longer than the
+// activity's recording are left out, as are all durations if it has no power.
+func PowerCurve(summary *model.ActivitySummary, streams *model.Streams, durations []uint32) []*Best {
+	return curve(summary, resample(streams.Time, streams.Watts), durations)
+}
+
+// The best average speed of an activity for each duration, from which best pace follows.
+// Durations longer than the activity's recording are left out, as are all durations if it
+// has no velocity.
+func PaceCurve(summary *model.ActivitySummary, streams *model.Streams, durations []uint32) []*Best {
+	return curve(summary, resample(streams.Time, streams.VelocitySmooth), durations)
+}
+
+// The best of several curves for each duration, in the order of durations. Ties go to the
+// earliest curve, so a record is credited to the activity that set it first.
+func BestCurve(curves [][]*Best, durations []uint32) []*Best {
+	best := make(map[uint32]*Best)
+	for _, c := range curves {
+		for _, b := range c {
+			if previous, found := best[b.Duration]; !found || b.Value > previous.Value {
+				best[b.Duration] = b
+			}
+		}
+	}
+
+	combined := make([]*Best, 0, len(durations))
+	for _, duration := range durations {
+		if b, found := best[duration]; found {
+			combined = append(combined, b)
+		}
+	}
+	return combined
+}
+
+func curve(summary *model.ActivitySummary, values []float64, durations []uint32) []*Best {
+	if !hasNonZero(values) {
+		return make([]*Best, 0)
+	}
+
+	bests := make([]*Best, 0, len(durations))
+	for _, duration := range durations {
+		if value, ok := maxAverage(values, int(duration)); ok {
+			bests = append(bests, &Best{
+				Duration:   duration,
+				Value:      value,
+				ActivityId: summary.Id,
+				Date:       localDate(summary.StartDateLocal),
+			})
+		}
+	}
+	return bests
+}
+
+// The greatest average of window consecutive values, found with a running sum so each
+// window takes constant time even for rides of many hours. Not ok if there are fewer values.
+func maxAverage(values []float64, window int) (float64, bool) {
+	if window <= 0 || len(values) < window {
+		return 0, false
+	}
+
+	sum := 0.0
+	for _, v := range values[:window] {
+		sum += v
+	}
+
+	max := sum
+	for i := window; i < len(values); i++ {
+		sum += values[i] - values[i-window]
+		if sum > max {
+			max = sum
+		}
+	}
+	return max / float64(window), true
+}
@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestPowerCurve(t *testing.T) {
+	summary := &model.ActivitySummary{Id: 3, StartDateLocal: time.Date(2015, 6, 1, 8, 0, 0, 0, time.UTC)}
+
+	// 10 minutes at 200 watts with a 5 second sprint at 800 and a minute at 400
+	streams := constantStreams(600, 1, 200, 0)
+	for i := 100; i < 105; i++ {
+		streams.Watts[i] = 800
+	}
+	for i := 300; i < 360; i++ {
+		streams.Watts[i] = 400
+	}
+
+	curve := PowerCurve(summary, streams, StandardDurations)
+
+	expected := []*Best{
+		{Duration: 5, Value: 800},
+		{Duration: 60, Value: 400},
+		// The sprint is within the same 5 minutes as the minute at 400
+		{Duration: 300, Value: (5*800 + 60*400 + 235*200) / 300.0},
+	}
+	if len(curve) != len(expected) {
+		t.Fatalf("Curve length was not as expected. expected=%d, actual=%d", len(expected), len(curve))
+	}
+	for i, e := range expected {
+		if curve[i].Duration != e.Duration || math.Abs(curve[i].Value-e.Value) > 1e-9 || curve[i].ActivityId != 3 ||
+			!curve[i].Date.Equal(time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("Best was not as expected. expected=%+v, actual=%+v", e, curve[i])
+		}
+	}
+
+	if curve := PowerCurve(summary, &model.Streams{}, StandardDurations); len(curve) != 0 {
+		t.Fatalf("Expected an empty curve without power but got %v", curve)
+	}
+}
+
+func TestPaceCurve(t *testing.T) {
+	summary := &model.ActivitySummary{Id: 4}
+	streams := &model.Streams{Time: []uint32{0, 1, 2, 3, 4, 5, 6}, VelocitySmooth: []float32{3, 3, 4, 4, 4, 4, 4}}
+
+	curve := PaceCurve(summary, streams, []uint32{5, 7, 8})
+	if len(curve) != 2 || curve[0].Value != 4 || math.Abs(curve[1].Value-26.0/7) > 1e-6 {
+		t.Fatalf("Curve was not as expected. actual=%v", curve)
+	}
+}
+
+func TestBestCurve(t *testing.T) {
+	first := []*Best{{Duration: 5, Value: 900, ActivityId: 1}, {Duration: 60, Value: 400, ActivityId: 1}}
+	second := []*Best{{Duration: 5, Value: 850, ActivityId: 2}, {Duration: 60, Value: 400, ActivityId: 2}, {Duration: 300, Value: 320, ActivityId: 2}}
+
+	best := BestCurve([][]*Best{first, second}, StandardDurations)
+
+	expected := []*Best{first[0], first[1], second[2]}
+	if len(best) != len(expected) {
+		t.Fatalf("Curve length was not as expected. expected=%d, actual=%d", len(expected), len(best))
+	}
+	for i := range expected {
+		if best[i] != expected[i] {
+			t.Fatalf("Best was not as expected. expected=%+v, actual=%+v", expected[i], best[i])
+		}
+	}
+}
+
+func TestMaxAverage(t *testing.T) {
+	// A 6 hour ride of 1 second samples
+	values := make([]float64, 6*3600)
+	for i := range values {
+		values[i] = float64(i % 100)
+	}
+	values[10000] = 1000
+
+	if max, ok := maxAverage(values, 1); !ok || max != 1000 {
+		t.Fatalf("Max was not as expected. expected=1000, actual=%f", max)
+	}
+	if max, ok := maxAverage(values, 100); !ok || max != (4950-values[10000%100]+1000)/100 {
+		t.Fatalf("Max was not as expected. expected=%f, actual=%f", (4950-values[10000%100]+1000)/100, max)
+	}
+	if _, ok := maxAverage(values, len(values)+1); ok {
+		t.Fatalf("Expected no average for a window longer than the values")
+	}
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// The efforts of a group on a segment, fastest first.
+type SegmentRanking struct {
+	Segment *model.Segment
+	Efforts []*RankedEffort
+}
+
+// An activity's fastest effort on a segment, ranked among the group.
+type RankedEffort struct {
+	Activity *model.Activity
+	Effort   *model.SegmentEffort
+	Rank     int    // 1 for the fastest. Tied efforts share a rank.
+	Behind   uint32 // Seconds slower than the fastest
+}
+
+// Rank the activities of a group, such as a group ride, on each segment at least two of them
+// have efforts on. Segments are in the order they were first ridden in the activities, and an
+// activity with several efforts on a segment is ranked by its fastest.
+func RankSharedSegments(activities []*model.Activity) []*SegmentRanking {
+	rankings := make(map[model.SegmentId]*SegmentRanking)
+	order := make([]model.SegmentId, 0)
+	for _, activity := range activities {
+		fastest := make(map[model.SegmentId]*model.SegmentEffort)
+		for _, effort := range activity.SegmentEfforts {
+			if previous, found := fastest[effort.Segment.Id]; !found || effort.ElapsedTime < previous.ElapsedTime {
+				fastest[effort.Segment.Id] = effort
+			}
+		}
+
+		for _, effort := range activity.SegmentEfforts {
+			if fastest[effort.Segment.Id] != effort {
+				continue
+			}
+
+			ranking, found := rankings[effort.Segment.Id]
+			if !found {
+				ranking = &SegmentRanking{Segment: effort.Segment}
+				rankings[effort.Segment.Id] = ranking
+				order = append(order, effort.Segment.Id)
+			}
+			ranking.Efforts = append(ranking.Efforts, &RankedEffort{Activity: activity, Effort: effort})
+		}
+	}
+
+	shared := make([]*SegmentRanking, 0)
+	for _, segmentId := range order {
+		ranking := rankings[segmentId]
+		if len(ranking.Efforts) < 2 {
+			continue
+		}
+
+		efforts := ranking.Efforts
+		sort.SliceStable(efforts, func(i, j int) bool { return efforts[i].Effort.ElapsedTime < efforts[j].Effort.ElapsedTime })
+		for i, effort := range efforts {
+			effort.Rank = i + 1
+			if i > 0 && effort.Effort.ElapsedTime == efforts[i-1].Effort.ElapsedTime {
+				effort.Rank = efforts[i-1].Rank
+			}
+			effort.Behind = effort.Effort.ElapsedTime - efforts[0].Effort.ElapsedTime
+		}
+		shared = append(shared, ranking)
+	}
+	return shared
+}
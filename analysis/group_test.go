@@ -0,0 +1,55 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestRankSharedSegments(t *testing.T) {
+	climb := &model.Segment{Id: 1, Name: "Climb"}
+	sprint := &model.Segment{Id: 2, Name: "Sprint"}
+	solo := &model.Segment{Id: 3, Name: "Solo"}
+
+	activities := []*model.Activity{
+		{Id: 100, SegmentEfforts: []*model.SegmentEffort{
+			{Id: 1, Segment: sprint, ElapsedTime: 30},
+			{Id: 2, Segment: climb, ElapsedTime: 600},
+			{Id: 3, Segment: solo, ElapsedTime: 100},
+		}},
+		{Id: 200, SegmentEfforts: []*model.SegmentEffort{
+			{Id: 4, Segment: climb, ElapsedTime: 640},
+			{Id: 5, Segment: sprint, ElapsedTime: 28},
+			{Id: 6, Segment: climb, ElapsedTime: 580}, // A second lap, which is faster
+		}},
+		{Id: 300, SegmentEfforts: []*model.SegmentEffort{
+			{Id: 7, Segment: sprint, ElapsedTime: 30},
+		}},
+	}
+
+	rankings := RankSharedSegments(activities)
+	if len(rankings) != 2 || rankings[0].Segment != sprint || rankings[1].Segment != climb {
+		t.Fatalf("Rankings were not as expected. actual=%v", rankings)
+	}
+
+	expected := [][]struct {
+		effortId model.SegmentEffortId
+		rank     int
+		behind   uint32
+	}{
+		{{5, 1, 0}, {1, 2, 2}, {7, 2, 2}},
+		{{6, 1, 0}, {2, 2, 20}},
+	}
+	for i, ranking := range rankings {
+		if len(ranking.Efforts) != len(expected[i]) {
+			t.Fatalf("Effort count was not as expected. expected=%d, actual=%d", len(expected[i]), len(ranking.Efforts))
+		}
+		for j, e := range expected[i] {
+			actual := ranking.Efforts[j]
+			if actual.Effort.Id != e.effortId || actual.Rank != e.rank || actual.Behind != e.behind {
+				t.Fatalf("Ranked effort was not as expected. expected=%v, actual=%d %d %d",
+					e, actual.Effort.Id, actual.Rank, actual.Behind)
+			}
+		}
+	}
+}
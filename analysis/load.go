@@ -0,0 +1,184 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// Time constants of training loads, in days
+const (
+	ChronicDays = 42
+	AcuteDays   = 7
+)
+
+// Samples further apart than this are a pause in recording, which is left out rather than
+// filled in.
+const maxSampleGap = 5 // Seconds
+
+// Window normalized power is averaged over
+const normalizedPowerWindow = 30 // Seconds
+
+// An athlete's thresholds. Either may be 0 if not known.
+type Thresholds struct {
+	Ftp       float64 // Functional threshold power, in watts
+	HeartRate float64 // Lactate threshold heart rate, in beats/min
+}
+
+// Training stress of an activity, from power if possible, otherwise from heart rate.
+type Stress struct {
+	ActivityId      model.ActivityId
+	Date            time.Time // Local date the activity started on
+	HeartRate       bool      // Whether the stress was estimated from heart rate (hrTSS)
+	Duration        uint64    // Recorded seconds the stress is over
+	NormalizedPower float64   // Watts, or 0 if estimated from heart rate
+	IntensityFactor float64
+	TSS             float64 // Training stress score; an hour at threshold is 100
+}
+
+// Training loads at the end of a day.
+type Load struct {
+	Date time.Time
+	TSS  float64 // Total of the day's activities
+	CTL  float64 // Chronic training load, or fitness
+	ATL  float64 // Acute training load, or fatigue
+	TSB  float64 // Training stress balance, or form: the previous day's CTL less its ATL
+}
+
+// Normalized power of a watts stream: the fourth root of the mean of the fourth powers of
+// 30 second rolling averages. It reflects the physiological cost of variable efforts better
+// than average power. Returns 0 if there is no power.
+func NormalizedPower(streams *model.Streams) float64 {
+	watts := resample(streams.Time, streams.Watts)
+	if len(watts) == 0 {
+		return 0
+	}
+
+	window := normalizedPowerWindow
+	if len(watts) < window {
+		window = len(watts)
+	}
+
+	sum := 0.0
+	for _, w := range watts[:window] {
+		sum += w
+	}
+
+	total := math.Pow(sum/float64(window), 4)
+	for i := window; i < len(watts); i++ {
+		sum += watts[i] - watts[i-window]
+		total += math.Pow(sum/float64(window), 4)
+	}
+
+	return math.Pow(total/float64(len(watts)-window+1), 0.25)
+}
+
+// The training stress of an activity. Power is used when the activity has a watts stream
+// and FTP is known. Otherwise heart rate is used if the threshold heart rate is known,
+// scoring each second by the square of its fraction of threshold so an hour at threshold is
+// also 100. Returns nil if neither can be used.
+func ActivityStress(summary *model.ActivitySummary, streams *model.Streams, thresholds Thresholds) *Stress {
+	stress := &Stress{ActivityId: summary.Id, Date: localDate(summary.StartDateLocal)}
+
+	if watts := resample(streams.Time, streams.Watts); thresholds.Ftp > 0 && hasNonZero(watts) {
+		stress.Duration = uint64(len(watts))
+		stress.NormalizedPower = NormalizedPower(streams)
+		stress.IntensityFactor = stress.NormalizedPower / thresholds.Ftp
+		stress.TSS = float64(stress.Duration) * stress.IntensityFactor * stress.IntensityFactor / 3600 * 100
+		return stress
+	}
+
+	if heartrate := resample(streams.Time, streams.Heartrate); thresholds.HeartRate > 0 && hasNonZero(heartrate) {
+		total := 0.0
+		for _, hr := range heartrate {
+			total += (hr / thresholds.HeartRate) * (hr / thresholds.HeartRate)
+		}
+
+		stress.HeartRate = true
+		stress.Duration = uint64(len(heartrate))
+		stress.IntensityFactor = math.Sqrt(total / float64(len(heartrate)))
+		stress.TSS = total / 3600 * 100
+		return stress
+	}
+
+	return nil
+}
+
+// Daily training loads from the day of the first activity through the given date, or
+// through the day of the last activity if it is the zero time. Loads are exponentially
+// weighted averages of daily TSS, starting from 0.
+func TrainingLoad(stresses []*Stress, through time.Time) []*Load {
+	if len(stresses) == 0 {
+		return make([]*Load, 0)
+	}
+
+	daily := make(map[time.Time]float64)
+	dates := make([]time.Time, 0, len(stresses))
+	for _, stress := range stresses {
+		if _, found := daily[stress.Date]; !found {
+			dates = append(dates, stress.Date)
+		}
+		daily[stress.Date] += stress.TSS
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	last := dates[len(dates)-1]
+	if !through.IsZero() {
+		last = localDate(through)
+	}
+
+	loads := make([]*Load, 0)
+	ctl, atl := 0.0, 0.0
+	for date := dates[0]; !date.After(last); date = date.AddDate(0, 0, 1) {
+		load := &Load{Date: date, TSS: daily[date], TSB: ctl - atl}
+		ctl += (load.TSS - ctl) / ChronicDays
+		atl += (load.TSS - atl) / AcuteDays
+		load.CTL = ctl
+		load.ATL = atl
+		loads = append(loads, load)
+	}
+	return loads
+}
+
+// One value per second of values sampled at times, holding each value until the next sample.
+// Gaps longer than maxSampleGap are left out.
+func resample(times []uint32, values []float32) []float64 {
+	if len(values) == 0 || len(values) != len(times) {
+		return nil
+	}
+
+	// Each sample adds at most maxSampleGap values, which bounds the size even if times wrapped
+	capacity := len(values) * maxSampleGap
+	if last := times[len(times)-1]; last >= times[0] && int(last-times[0])+1 < capacity {
+		capacity = int(last-times[0]) + 1
+	}
+	resampled := make([]float64, 0, capacity)
+	resampled = append(resampled, float64(values[0]))
+	for i := 1; i < len(values); i++ {
+		gap := times[i] - times[i-1]
+		if gap > maxSampleGap {
+			gap = 1
+		}
+		for s := uint32(1); s < gap; s++ {
+			resampled = append(resampled, float64(values[i-1]))
+		}
+		resampled = append(resampled, float64(values[i]))
+	}
+	return resampled
+}
+
+func hasNonZero(values []float64) bool {
+	for _, v := range values {
+		if v != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Midnight of the date of t, in UTC like StartDateLocal.
+func localDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,132 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestNormalizedPower(t *testing.T) {
+	// 30 seconds at 300 watts, then 30 seconds coasting
+	streams := &model.Streams{}
+	for i := 0; i < 60; i++ {
+		streams.Time = append(streams.Time, uint32(i))
+		if i < 30 {
+			streams.Watts = append(streams.Watts, 300)
+		} else {
+			streams.Watts = append(streams.Watts, 0)
+		}
+	}
+
+	if np := NormalizedPower(streams); math.Abs(np-203.093) > 0.001 {
+		t.Fatalf("Normalized power was not as expected. expected=203.093, actual=%f", np)
+	}
+
+	if np := NormalizedPower(&model.Streams{}); np != 0 {
+		t.Fatalf("Normalized power without watts was not as expected. expected=0, actual=%f", np)
+	}
+}
+
+func TestActivityStress_Power(t *testing.T) {
+	summary := &model.ActivitySummary{Id: 7, StartDateLocal: time.Date(2015, 3, 1, 21, 30, 0, 0, time.UTC)}
+	streams := constantStreams(3600, 1, 250, 150)
+
+	stress := ActivityStress(summary, streams, Thresholds{Ftp: 250, HeartRate: 160})
+	if stress == nil {
+		t.Fatalf("Expected stress from power")
+	}
+	if stress.HeartRate || stress.Duration != 3600 || math.Abs(stress.NormalizedPower-250) > 0.01 ||
+		math.Abs(stress.IntensityFactor-1) > 0.0001 || math.Abs(stress.TSS-100) > 0.01 {
+		t.Fatalf("Stress was not as expected. actual=%+v", stress)
+	}
+	if !stress.Date.Equal(time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Stress date was not as expected. expected=2015-03-01, actual=%s", stress.Date)
+	}
+}
+
+func TestActivityStress_HeartRate(t *testing.T) {
+	summary := &model.ActivitySummary{Id: 7}
+
+	// Samples every 2 seconds are filled in, but the 10 minute pause is left out
+	streams := constantStreams(1800, 2, 0, 150)
+	for i := 0; i < 900; i++ {
+		streams.Time = append(streams.Time, uint32(2400+2*i))
+		streams.Watts = append(streams.Watts, 0)
+		streams.Heartrate = append(streams.Heartrate, 150)
+	}
+
+	// Power is ignored without FTP
+	stress := ActivityStress(summary, constantStreams(3600, 1, 250, 150), Thresholds{HeartRate: 150})
+	if stress == nil || !stress.HeartRate || math.Abs(stress.TSS-100) > 0.01 {
+		t.Fatalf("Stress was not as expected. actual=%+v", stress)
+	}
+
+	stress = ActivityStress(summary, streams, Thresholds{Ftp: 250, HeartRate: 150})
+	if stress == nil {
+		t.Fatalf("Expected stress from heart rate")
+	}
+	if !stress.HeartRate || stress.Duration != 3598 || stress.NormalizedPower != 0 || stress.IntensityFactor != 1 ||
+		math.Abs(stress.TSS-99.94) > 0.01 {
+		t.Fatalf("Stress was not as expected. actual=%+v", stress)
+	}
+
+	if stress := ActivityStress(summary, &model.Streams{}, Thresholds{Ftp: 250, HeartRate: 150}); stress != nil {
+		t.Fatalf("Expected no stress without streams but got %+v", stress)
+	}
+}
+
+func TestTrainingLoad(t *testing.T) {
+	day := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+	stresses := []*Stress{
+		{Date: day, TSS: 60},
+		{Date: day, TSS: 40},
+		{Date: day.AddDate(0, 0, 2), TSS: 70},
+	}
+
+	loads := TrainingLoad(stresses, day.AddDate(0, 0, 3))
+	if len(loads) != 4 {
+		t.Fatalf("Load count was not as expected. expected=4, actual=%d", len(loads))
+	}
+
+	ctl, atl := 100.0/42, 100.0/7
+	expected := []*Load{
+		{Date: day, TSS: 100, CTL: ctl, ATL: atl, TSB: 0},
+		{Date: day.AddDate(0, 0, 1), TSS: 0, CTL: ctl * 41 / 42, ATL: atl * 6 / 7, TSB: ctl - atl},
+		{Date: day.AddDate(0, 0, 2), TSS: 70, CTL: ctl*41/42*41/42 + 70.0/42, ATL: atl*6/7*6/7 + 10, TSB: ctl*41/42 - atl*6/7},
+	}
+	for i, e := range expected {
+		actual := loads[i]
+		if !actual.Date.Equal(e.Date) || actual.TSS != e.TSS || math.Abs(actual.CTL-e.CTL) > 1e-9 ||
+			math.Abs(actual.ATL-e.ATL) > 1e-9 || math.Abs(actual.TSB-e.TSB) > 1e-9 {
+			t.Fatalf("Load was not as expected. expected=%+v, actual=%+v", e, actual)
+		}
+	}
+	if loads[3].TSS != 0 || loads[3].CTL >= loads[2].CTL {
+		t.Fatalf("Expected loads to decay after the last activity. actual=%+v", loads[3])
+	}
+
+	if loads := TrainingLoad(stresses, time.Time{}); len(loads) != 3 {
+		t.Fatalf("Load count was not as expected. expected=3, actual=%d", len(loads))
+	}
+}
+
+// Streams of constant power and heart rate, sampled every interval seconds.
+func constantStreams(seconds int, interval int, watts float32, heartrate float32) *model.Streams {
+	streams := &model.Streams{}
+	for i := 0; i < seconds; i += interval {
+		streams.Time = append(streams.Time, uint32(i))
+		streams.Watts = append(streams.Watts, watts)
+		streams.Heartrate = append(streams.Heartrate, heartrate)
+	}
+	return streams
+}
+
+func TestResample_Backwards(t *testing.T) {
+	// Times going backwards wrap when subtracted, and are treated like long gaps
+	resampled := resample([]uint32{0, 2, 1, 4294967295}, []float32{1, 2, 3, 4})
+	if len(resampled) != 5 || resampled[4] != 4 {
+		t.Fatalf("Resampled values were not as expected. actual=%v", resampled)
+	}
+}
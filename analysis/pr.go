@@ -0,0 +1,117 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/store"
+)
+
+// The efforts of an athlete on a segment and the personal records among them.
+type SegmentRecord struct {
+	Segment     *model.Segment
+	Efforts     []*store.Effort // Oldest first
+	Progression []*store.Effort // Each effort faster than every one before it, oldest first
+}
+
+// The personal record: the fastest effort, or the first of the fastest if tied.
+func (r *SegmentRecord) Best() *store.Effort {
+	return r.Progression[len(r.Progression)-1]
+}
+
+// The most recent effort.
+func (r *SegmentRecord) Latest() *store.Effort {
+	return r.Efforts[len(r.Efforts)-1]
+}
+
+// Seconds slower than the personal record the latest effort was, or 0 if it is the record.
+func (r *SegmentRecord) Gap() uint32 {
+	return r.Latest().Effort.ElapsedTime - r.Best().Effort.ElapsedTime
+}
+
+// A personal record, and by how much it beat the previous one.
+type Pr struct {
+	Segment     *model.Segment
+	Effort      *store.Effort
+	Improvement uint32 // Seconds faster than the previous record, or 0 for a first effort
+}
+
+// Reconstruct the personal records of each segment from all efforts, ordered by segment id.
+// Unlike SegmentEffort.PrRank, which only tells whether an effort ranked in the top 3 when it
+// was made, this gives the full history.
+func SegmentRecords(efforts []*store.Effort) []*SegmentRecord {
+	sorted := make([]*store.Effort, len(efforts))
+	copy(sorted, efforts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Effort.StartDate.Before(sorted[j].Effort.StartDate)
+	})
+
+	records := make(map[model.SegmentId]*SegmentRecord)
+	for _, effort := range sorted {
+		segment := effort.Effort.Segment
+		record, found := records[segment.Id]
+		if !found {
+			record = &SegmentRecord{Segment: segment}
+			records[segment.Id] = record
+		}
+
+		record.Efforts = append(record.Efforts, effort)
+		if len(record.Progression) == 0 || effort.Effort.ElapsedTime < record.Best().Effort.ElapsedTime {
+			record.Progression = append(record.Progression, effort)
+		}
+	}
+
+	ordered := make([]*SegmentRecord, 0, len(records))
+	for _, record := range records {
+		ordered = append(ordered, record)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Segment.Id < ordered[j].Segment.Id })
+	return ordered
+}
+
+// Every personal record of the segments, ordered by segment and then date.
+func Progression(records []*SegmentRecord) []*Pr {
+	prs := make([]*Pr, 0)
+	for _, record := range records {
+		for i, effort := range record.Progression {
+			pr := &Pr{Segment: record.Segment, Effort: effort}
+			if i > 0 {
+				pr.Improvement = record.Progression[i-1].Effort.ElapsedTime - effort.Effort.ElapsedTime
+			}
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// Personal records set since the given time, newest first. First efforts on a segment
+// are not included, since there was nothing to beat.
+func RecentPrs(records []*SegmentRecord, since time.Time) []*Pr {
+	recent := make([]*Pr, 0)
+	for _, pr := range Progression(records) {
+		if pr.Improvement > 0 && !pr.Effort.Effort.StartDate.Before(since) {
+			recent = append(recent, pr)
+		}
+	}
+
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].Effort.Effort.StartDate.After(recent[j].Effort.Effort.StartDate)
+	})
+	return recent
+}
+
+// Segments whose latest effort was before the given time, least recently attempted first.
+func StaleSegments(records []*SegmentRecord, before time.Time) []*SegmentRecord {
+	stale := make([]*SegmentRecord, 0)
+	for _, record := range records {
+		if record.Latest().Effort.StartDate.Before(before) {
+			stale = append(stale, record)
+		}
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].Latest().Effort.StartDate.Before(stale[j].Latest().Effort.StartDate)
+	})
+	return stale
+}
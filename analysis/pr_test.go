@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/store"
+)
+
+func TestSegmentRecords(t *testing.T) {
+	hawkHill := &model.Segment{Id: 229781, Name: "Hawk Hill"}
+	oldLa := &model.Segment{Id: 1000, Name: "Old La Honda"}
+	day := time.Date(2015, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	efforts := []*store.Effort{
+		testEffort(5, hawkHill, day.AddDate(0, 2, 0), 600), // Ties the record, so isn't one
+		testEffort(1, hawkHill, day, 700),
+		testEffort(2, oldLa, day.AddDate(0, 0, 1), 1500),
+		testEffort(3, hawkHill, day.AddDate(0, 1, 0), 600),
+		testEffort(4, hawkHill, day.AddDate(0, 1, 15), 650),
+		testEffort(6, hawkHill, day.AddDate(0, 3, 0), 630),
+	}
+
+	records := SegmentRecords(efforts)
+	if len(records) != 2 || records[0].Segment != oldLa || records[1].Segment != hawkHill {
+		t.Fatalf("Records were not as expected. actual=%v", records)
+	}
+
+	record := records[1]
+	if len(record.Efforts) != 5 {
+		t.Fatalf("Effort count was not as expected. expected=5, actual=%d", len(record.Efforts))
+	}
+	if actual := effortIds(record.Progression); actual != "1,3" {
+		t.Fatalf("Progression was not as expected. expected=1,3, actual=%s", actual)
+	}
+	if record.Best().Effort.Id != 3 || record.Latest().Effort.Id != 6 || record.Gap() != 30 {
+		t.Fatalf("Best, latest and gap were not as expected. best=%d, latest=%d, gap=%d",
+			record.Best().Effort.Id, record.Latest().Effort.Id, record.Gap())
+	}
+
+	prs := Progression(records)
+	expected := []struct {
+		effortId    model.SegmentEffortId
+		improvement uint32
+	}{{2, 0}, {1, 0}, {3, 100}}
+	if len(prs) != len(expected) {
+		t.Fatalf("Progression length was not as expected. expected=%d, actual=%d", len(expected), len(prs))
+	}
+	for i, e := range expected {
+		if prs[i].Effort.Effort.Id != e.effortId || prs[i].Improvement != e.improvement {
+			t.Fatalf("PR was not as expected. expected=%v, actual=%d %d", e, prs[i].Effort.Effort.Id, prs[i].Improvement)
+		}
+	}
+
+	if recent := RecentPrs(records, day.AddDate(0, 0, 10)); len(recent) != 1 || recent[0].Effort.Effort.Id != 3 {
+		t.Fatalf("Recent PRs were not as expected. actual=%v", recent)
+	}
+	if recent := RecentPrs(records, day.AddDate(0, 2, 0)); len(recent) != 0 {
+		t.Fatalf("Expected no recent PRs but got %v", recent)
+	}
+
+	stale := StaleSegments(records, day.AddDate(0, 2, 0))
+	if len(stale) != 1 || stale[0].Segment != oldLa {
+		t.Fatalf("Stale segments were not as expected. actual=%v", stale)
+	}
+}
+
+func testEffort(id model.SegmentEffortId, segment *model.Segment, date time.Time, elapsedTime uint32) *store.Effort {
+	return &store.Effort{
+		ActivityId: model.ActivityId(id * 10),
+		Effort:     &model.SegmentEffort{Id: id, Segment: segment, StartDate: date, StartDateLocal: date, ElapsedTime: elapsedTime},
+	}
+}
+
+func effortIds(efforts []*store.Effort) string {
+	ids := ""
+	for i, effort := range efforts {
+		if i > 0 {
+			ids += ","
+		}
+		ids += fmt.Sprintf("%d", effort.Effort.Id)
+	}
+	return ids
+}
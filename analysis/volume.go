@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// A calendar period to total activities by
+type Period string
+
+const (
+	Week  Period = "week" // ISO 8601 weeks, starting on Monday
+	Month Period = "month"
+	Year  Period = "year"
+)
+
+func ParsePeriod(period string) (Period, error) {
+	switch p := Period(strings.ToLower(period)); p {
+	case Week, Month, Year:
+		return p, nil
+	}
+	return "", fmt.Errorf("Unknown period %s", period)
+}
+
+// Totals of the activities started in a period.
+type Volume struct {
+	Start         time.Time // Midnight of the first day of the period, as a local date in UTC like StartDateLocal
+	Period        Period
+	Count         int
+	Distance      float64 // Meters
+	MovingTime    uint64  // Seconds
+	ElevationGain float64 // Meters
+}
+
+// The period as 2014-W01, 2014-01 or 2014.
+func (v *Volume) Label() string {
+	switch v.Period {
+	case Week:
+		year, week := v.Start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case Month:
+		return v.Start.Format("2006-01")
+	}
+	return v.Start.Format("2006")
+}
+
+// Average moving speed in meters/sec, or 0 if there was no moving time.
+func (v *Volume) AverageSpeed() float64 {
+	if v.MovingTime == 0 {
+		return 0
+	}
+	return v.Distance / float64(v.MovingTime)
+}
+
+// Total activities per period, oldest first. Activities are placed by the local date they
+// started on, so late evening activities count toward the day the athlete did them.
+// Only activities of the given types are included, or all if types is empty. Periods without
+// activities between the first and last are included with zero totals, so trends show gaps.
+func VolumeBy(summaries []*model.ActivitySummary, period Period, types []string) []*Volume {
+	volumes := make(map[time.Time]*Volume)
+	for _, summary := range summaries {
+		if !HasType(summary.Type, types) {
+			continue
+		}
+
+		start := periodStart(summary.StartDateLocal, period)
+		volume, found := volumes[start]
+		if !found {
+			volume = &Volume{Start: start, Period: period}
+			volumes[start] = volume
+		}
+
+		volume.Count++
+		volume.Distance += float64(summary.Distance)
+		volume.MovingTime += uint64(summary.MovingTime)
+		volume.ElevationGain += float64(summary.TotalElevationGain)
+	}
+
+	if len(volumes) == 0 {
+		return make([]*Volume, 0)
+	}
+
+	starts := make([]time.Time, 0, len(volumes))
+	for start := range volumes {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	sorted := make([]*Volume, 0, len(volumes))
+	for start := starts[0]; !start.After(starts[len(starts)-1]); start = nextPeriod(start, period) {
+		volume, found := volumes[start]
+		if !found {
+			volume = &Volume{Start: start, Period: period}
+		}
+		sorted = append(sorted, volume)
+	}
+	return sorted
+}
+
+// Whether activityType is one of types, ignoring case. Every type matches empty types.
+func HasType(activityType string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if strings.EqualFold(activityType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// The start of the period containing the local date of t.
+func periodStart(t time.Time, period Period) time.Time {
+	day := localDate(t)
+	switch period {
+	case Week:
+		// Weekday counts from Sunday; ISO weeks start on Monday
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day.AddDate(0, 0, 1-day.YearDay())
+}
+
+func nextPeriod(start time.Time, period Period) time.Time {
+	switch period {
+	case Week:
+		return start.AddDate(0, 0, 7)
+	case Month:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(1, 0, 0)
+}
@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestVolumeBy_Week(t *testing.T) {
+	summaries := []*model.ActivitySummary{
+		// Sunday evening locally, though Monday in UTC
+		testSummary("Ride", time.Date(2015, 1, 4, 22, 0, 0, 0, time.UTC), time.Date(2015, 1, 5, 6, 0, 0, 0, time.UTC), 20000, 3600, 100),
+		testSummary("Run", time.Date(2015, 1, 5, 7, 0, 0, 0, time.UTC), time.Date(2015, 1, 5, 7, 0, 0, 0, time.UTC), 10000, 3000, 50),
+		testSummary("Ride", time.Date(2015, 1, 11, 9, 0, 0, 0, time.UTC), time.Date(2015, 1, 11, 9, 0, 0, 0, time.UTC), 40000, 7200, 300),
+		testSummary("Ride", time.Date(2015, 1, 20, 9, 0, 0, 0, time.UTC), time.Date(2015, 1, 20, 9, 0, 0, 0, time.UTC), 30000, 3600, 0),
+	}
+
+	volumes := VolumeBy(summaries, Week, []string{"ride"})
+
+	expected := []struct {
+		label      string
+		count      int
+		distance   float64
+		movingTime uint64
+		elevation  float64
+	}{
+		// 2015-01-01 is a Thursday, so ISO week 1 started on 2014-12-29
+		{"2015-W01", 1, 20000, 3600, 100},
+		{"2015-W02", 1, 40000, 7200, 300},
+		{"2015-W03", 0, 0, 0, 0},
+		{"2015-W04", 1, 30000, 3600, 0},
+	}
+	if len(volumes) != len(expected) {
+		t.Fatalf("Volume count was not as expected. expected=%d, actual=%d", len(expected), len(volumes))
+	}
+	for i, e := range expected {
+		volume := volumes[i]
+		if volume.Label() != e.label || volume.Count != e.count || volume.Distance != e.distance ||
+			volume.MovingTime != e.movingTime || volume.ElevationGain != e.elevation {
+			t.Fatalf("Volume was not as expected. expected=%v, actual=%s %+v", e, volume.Label(), volume)
+		}
+	}
+
+	if start := volumes[0].Start; !start.Equal(time.Date(2014, 12, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Week start was not as expected. expected=2014-12-29, actual=%s", start)
+	}
+	if speed := volumes[1].AverageSpeed(); speed != 40000.0/7200 {
+		t.Fatalf("Average speed was not as expected. expected=%f, actual=%f", 40000.0/7200, speed)
+	}
+}
+
+func TestVolumeBy_MonthAndYear(t *testing.T) {
+	summaries := []*model.ActivitySummary{
+		testSummary("Ride", time.Date(2014, 11, 30, 9, 0, 0, 0, time.UTC), time.Date(2014, 11, 30, 9, 0, 0, 0, time.UTC), 1000, 100, 10),
+		testSummary("Run", time.Date(2015, 1, 31, 9, 0, 0, 0, time.UTC), time.Date(2015, 1, 31, 9, 0, 0, 0, time.UTC), 2000, 200, 20),
+	}
+
+	months := VolumeBy(summaries, Month, nil)
+	labels := []string{"2014-11", "2014-12", "2015-01"}
+	if len(months) != len(labels) {
+		t.Fatalf("Month count was not as expected. expected=%d, actual=%d", len(labels), len(months))
+	}
+	for i, label := range labels {
+		if months[i].Label() != label {
+			t.Fatalf("Month was not as expected. expected=%s, actual=%s", label, months[i].Label())
+		}
+	}
+
+	years := VolumeBy(summaries, Year, nil)
+	if len(years) != 2 || years[0].Label() != "2014" || years[1].Label() != "2015" || years[1].Distance != 2000 {
+		t.Fatalf("Years were not as expected. actual=%+v", years)
+	}
+
+	if volumes := VolumeBy(summaries, Year, []string{"Swim"}); len(volumes) != 0 {
+		t.Fatalf("Expected no volumes but got %v", volumes)
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	if period, err := ParsePeriod("Month"); err != nil || period != Month {
+		t.Fatalf("Period was not as expected. expected=%s, actual=%s, error=%v", Month, period, err)
+	}
+	if _, err := ParsePeriod("fortnight"); err == nil {
+		t.Fatalf("Expected an error for an unknown period")
+	}
+}
+
+func testSummary(activityType string, local time.Time, utc time.Time, distance float32, movingTime uint32, elevation float32) *model.ActivitySummary {
+	return &model.ActivitySummary{
+		Type:               activityType,
+		StartDate:          utc,
+		StartDateLocal:     local,
+		Distance:           distance,
+		MovingTime:         movingTime,
+		TotalElevationGain: elevation,
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alecholmes/strava/auth"
+	"github.com/alecholmes/strava/config"
+)
+
+// How long to wait for the athlete to authorize in their browser
+const authTimeout = 5 * time.Minute
+
+// Runs the OAuth flow with a local web server receiving the redirect, then saves the token
+// in the credential store for the profile, so other commands don't need --accessToken.
+func authLoginMain(flags *flag.FlagSet, args []string) error {
+	clientIdFlag := flags.String("clientId", "", "client id of your Strava API application; required unless the profile has logged in before")
+	clientSecretFlag := flags.String("clientSecret", "", "client secret of your Strava API application; required unless the profile has logged in before")
+	scopeFlag := flags.String("scope", strings.Join([]string{auth.ReadScope, auth.ProfileReadAllScope, auth.ActivityReadAllScope}, ","),
+		"comma separated scopes to request")
+	portFlag := flags.Int("port", 8089, "local port to receive the authorization redirect on")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	credentialStore, err := config.OpenCredentials(dir)
+	if err != nil {
+		return fmt.Errorf("reading credentials: %s", err)
+	}
+
+	profile := profileName(flags)
+	credentials := &config.Credentials{ClientId: *clientIdFlag, ClientSecret: *clientSecretFlag}
+	if previous := credentialStore.Get(profile); previous != nil && credentials.ClientId == "" && credentials.ClientSecret == "" {
+		credentials.ClientId = previous.ClientId
+		credentials.ClientSecret = previous.ClientSecret
+	}
+	if credentials.ClientId == "" || credentials.ClientSecret == "" {
+		return usageErrorf("--clientId and --clientSecret are required")
+	}
+
+	authenticator := auth.NewAuthenticator(credentials.ClientId, credentials.ClientSecret)
+	credentials.Token, err = authorize(authenticator, strings.Split(*scopeFlag, ","), *portFlag)
+	if err != nil {
+		return err
+	}
+
+	if err := credentialStore.Put(profile, credentials); err != nil {
+		return fmt.Errorf("saving credentials: %s", err)
+	}
+
+	if athlete := credentials.Token.Athlete; athlete != nil {
+		fmt.Printf("Logged in as %s %s.\n", athlete.FirstName, athlete.LastName)
+	}
+	fmt.Printf("Saved credentials for profile %s.\n", profile)
+	return nil
+}
+
+// Have the athlete authorize in their browser, receiving the redirect on a local port,
+// and exchange the code for a token.
+func authorize(authenticator *auth.Authenticator, scopes []string, port int) (*auth.Token, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("listening for the authorization redirect: %s", err)
+	}
+	defer listener.Close()
+
+	// Guards against redirects that didn't come from this login
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		return nil, err
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path != "/callback":
+			http.NotFound(w, r)
+			return
+		case query.Get("state") != state:
+			http.Error(w, "Unexpected state", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			fmt.Fprintln(w, "Authorization was denied. You can close this window.")
+			errs <- fmt.Errorf("authorization denied: %s", query.Get("error"))
+			return
+		}
+		fmt.Fprintln(w, "Authorized. You can close this window.")
+		codes <- query.Get("code")
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	redirectUri := fmt.Sprintf("http://localhost:%d/callback", port)
+	fmt.Printf("Open this url in a browser to authorize:\n\n%s\n\n", authenticator.AuthorizeUrl(redirectUri, scopes, state))
+
+	select {
+	case code := <-codes:
+		token, err := authenticator.Exchange(code)
+		if err != nil {
+			return nil, fmt.Errorf("exchanging authorization code: %s", err)
+		}
+		return token, nil
+	case err := <-errs:
+		return nil, err
+	case <-time.After(authTimeout):
+		return nil, errors.New("timed out waiting for authorization")
+	}
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+const stravaBaseUrl = "https://www.strava.com"
+
+const (
+	authorizeUrl = "/oauth/authorize"
+	tokenUrl     = "/oauth/token"
+)
+
+// Scopes an application can request. Without any, only public data can be read.
+const (
+	ReadScope            = "read"
+	ReadAllScope         = "read_all"
+	ProfileReadAllScope  = "profile:read_all"
+	ActivityReadScope    = "activity:read"
+	ActivityReadAllScope = "activity:read_all"
+	ActivityWriteScope   = "activity:write"
+)
+
+// Tokens granted to an application for an athlete. Access tokens expire after a few hours,
+// and are replaced using the refresh token.
+type Token struct {
+	TokenType    string         `json:"token_type"`
+	AccessToken  string         `json:"access_token"`
+	RefreshToken string         `json:"refresh_token"`
+	ExpiresAt    int64          `json:"expires_at"` // Seconds since epoch
+	Athlete      *model.Athlete `json:"athlete"`    // Only included when exchanging a code
+}
+
+// Whether the access token has expired, or will within a minute.
+func (t *Token) Expired(now time.Time) bool {
+	return now.Add(time.Minute).Unix() >= t.ExpiresAt
+}
+
+// Runs the OAuth flow that lets an athlete grant an application access, using the
+// application's client id and secret.
+type Authenticator struct {
+	clientId     string
+	clientSecret string
+	baseUrl      string
+	httpClient   *http.Client
+}
+
+func NewAuthenticator(clientId string, clientSecret string) *Authenticator {
+	return &Authenticator{clientId: clientId, clientSecret: clientSecret, baseUrl: stravaBaseUrl, httpClient: http.DefaultClient}
+}
+
+// The url to send the athlete to. After they approve access, Strava redirects them to
+// redirectUri with code and state parameters; the code is exchanged for a token.
+// redirectUri's host must match the application's authorization callback domain.
+func (a *Authenticator) AuthorizeUrl(redirectUri string, scopes []string, state string) string {
+	params := url.Values{
+		"client_id":       {a.clientId},
+		"redirect_uri":    {redirectUri},
+		"response_type":   {"code"},
+		"approval_prompt": {"auto"},
+		"scope":           {strings.Join(scopes, ",")},
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+	return a.baseUrl + authorizeUrl + "?" + params.Encode()
+}
+
+// Exchange the code from an authorization redirect for a token.
+func (a *Authenticator) Exchange(code string) (*Token, error) {
+	form := a.credentials()
+	form.Set("code", code)
+	form.Set("grant_type", "authorization_code")
+	return a.token(form)
+}
+
+// Get a new access token. The returned refresh token should be used for the next refresh.
+func (a *Authenticator) Refresh(refreshToken string) (*Token, error) {
+	form := a.credentials()
+	form.Set("refresh_token", refreshToken)
+	form.Set("grant_type", "refresh_token")
+	return a.token(form)
+}
+
+func (a *Authenticator) credentials() url.Values {
+	return url.Values{"client_id": {a.clientId}, "client_secret": {a.clientSecret}}
+}
+
+func (a *Authenticator) token(form url.Values) (*Token, error) {
+	request, _ := http.NewRequest("POST", a.baseUrl+tokenUrl, strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := a.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("Unexpected HTTP response %v: %s", response.Status, body)
+	}
+
+	var token Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAuthenticator_AuthorizeUrl(t *testing.T) {
+	authorize := NewAuthenticator("5", "shh").AuthorizeUrl("http://localhost:8080/callback",
+		[]string{ReadScope, ActivityReadAllScope}, "xyz")
+
+	parsed, err := url.Parse(authorize)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing url. error=%s", err)
+	}
+	query := parsed.Query()
+	if parsed.Host != "www.strava.com" || parsed.Path != authorizeUrl || query.Get("client_id") != "5" ||
+		query.Get("redirect_uri") != "http://localhost:8080/callback" || query.Get("response_type") != "code" ||
+		query.Get("scope") != "read,activity:read_all" || query.Get("state") != "xyz" || query.Get("client_secret") != "" {
+		t.Fatalf("Unexpected authorize url %s", authorize)
+	}
+}
+
+func TestAuthenticator_Exchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Method != "POST" || r.URL.Path != tokenUrl {
+			t.Errorf("Unexpected request. method=%s, path=%s", r.Method, r.URL.Path)
+		}
+		if r.PostForm.Get("client_id") != "5" || r.PostForm.Get("client_secret") != "shh" ||
+			r.PostForm.Get("code") != "abc" || r.PostForm.Get("grant_type") != "authorization_code" {
+			t.Errorf("Unexpected form %v", r.PostForm)
+		}
+		w.Write([]byte(`{"token_type": "Bearer", "access_token": "access", "refresh_token": "refresh",
+			"expires_at": 1568775134, "athlete": {"id": 227615, "firstname": "John"}}`))
+	}))
+	defer server.Close()
+
+	token, err := testAuthenticator(server).Exchange("abc")
+	if err != nil {
+		t.Fatalf("Unexpected error for Exchange. error=%s", err)
+	}
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" || token.ExpiresAt != 1568775134 ||
+		token.Athlete == nil || token.Athlete.Id != 227615 {
+		t.Fatalf("Unexpected token %+v", token)
+	}
+}
+
+func TestAuthenticator_Refresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("refresh_token") != "refresh" || r.PostForm.Get("grant_type") != "refresh_token" {
+			t.Errorf("Unexpected form %v", r.PostForm)
+		}
+		w.Write([]byte(`{"token_type": "Bearer", "access_token": "access2", "refresh_token": "refresh2", "expires_at": 1568775134}`))
+	}))
+	defer server.Close()
+
+	token, err := testAuthenticator(server).Refresh("refresh")
+	if err != nil {
+		t.Fatalf("Unexpected error for Refresh. error=%s", err)
+	}
+	if token.AccessToken != "access2" || token.RefreshToken != "refresh2" || token.Athlete != nil {
+		t.Fatalf("Unexpected token %+v", token)
+	}
+}
+
+func TestAuthenticator_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Bad Request", "errors": [{"field": "code", "code": "invalid"}]}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	if _, err := testAuthenticator(server).Exchange("bad"); err == nil {
+		t.Fatalf("Expected error for bad request")
+	}
+}
+
+func TestToken_Expired(t *testing.T) {
+	token := &Token{ExpiresAt: time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC).Unix()}
+	if token.Expired(time.Date(2019, 1, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected token not to be expired an hour early")
+	}
+	if !token.Expired(time.Date(2019, 1, 1, 11, 59, 30, 0, time.UTC)) {
+		t.Fatalf("Expected token to be expired within a minute of expiry")
+	}
+}
+
+func testAuthenticator(server *httptest.Server) *Authenticator {
+	authenticator := NewAuthenticator("5", "shh")
+	authenticator.baseUrl = server.URL
+	return authenticator
+}
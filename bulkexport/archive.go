@@ -0,0 +1,152 @@
+package bulkexport
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/alecholmes/strava/model"
+)
+
+const activitiesCsv = "activities.csv"
+
+// An activity read from an export.
+type Activity struct {
+	Activity *model.Activity
+	Streams  *model.Streams // Nil if the activity has no track file or it couldn't be decoded
+}
+
+// The activities read from an export, oldest first.
+type Import struct {
+	Activities []*Activity
+	Skipped    []string // Track files that couldn't be decoded, with the reason
+}
+
+// Read a Strava bulk export zip. Activities come from activities.csv; streams come from the
+// GPX, TCX or FIT file each activity refers to, which may be gzipped. Track files that can't be
+// decoded are skipped rather than failing the import.
+func ImportFile(zipPath string) (*Import, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return importZip(&reader.Reader)
+}
+
+// Read a Strava bulk export zip from r. See ImportFile.
+func ImportReader(r io.ReaderAt, size int64) (*Import, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return importZip(reader)
+}
+
+func importZip(reader *zip.Reader) (*Import, error) {
+	// Exports may be unzipped and rezipped under a top level directory, so files are found by suffix
+	files := make(map[string]*zip.File, len(reader.File))
+	prefix := ""
+	for _, file := range reader.File {
+		files[file.Name] = file
+		if path.Base(file.Name) == activitiesCsv {
+			prefix = strings.TrimSuffix(file.Name, activitiesCsv)
+		}
+	}
+
+	csvFile, found := files[prefix+activitiesCsv]
+	if !found {
+		return nil, fmt.Errorf("export has no %s", activitiesCsv)
+	}
+
+	csvReader, err := csvFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseActivitiesCsv(csvReader)
+	csvReader.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Import{Activities: make([]*Activity, 0, len(rows)), Skipped: make([]string, 0)}
+	for _, row := range rows {
+		activity := &Activity{Activity: row.activity}
+		result.Activities = append(result.Activities, activity)
+
+		if row.filename == "" {
+			continue
+		}
+
+		file, found := files[prefix+row.filename]
+		if !found {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: not in export", row.filename))
+			continue
+		}
+
+		streams, err := decodeTrackFile(file)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %s", row.filename, err))
+			continue
+		}
+		streams.ActivityId = row.activity.Id
+		activity.Streams = streams
+	}
+
+	return result, nil
+}
+
+func decodeTrackFile(file *zip.File) (*model.Streams, error) {
+	name := strings.ToLower(file.Name)
+	gzipped := strings.HasSuffix(name, ".gz")
+	name = strings.TrimSuffix(name, ".gz")
+
+	decode, found := decoders[path.Ext(name)]
+	if !found {
+		return nil, fmt.Errorf("unsupported track file type %s", path.Ext(name))
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var r io.Reader = reader
+	if gzipped {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		r = gzipReader
+	}
+
+	return decode(r)
+}
+
+// A summary of the activity, for storing alongside it.
+func (a *Activity) Summary() *model.ActivitySummary {
+	activity := a.Activity
+	return &model.ActivitySummary{
+		Id:                 activity.Id,
+		Name:               activity.Name,
+		Type:               activity.Type,
+		Athlete:            activity.Athlete,
+		StartDate:          activity.StartDate,
+		StartDateLocal:     activity.StartDateLocal,
+		Timezone:           activity.Timezone,
+		MovingTime:         activity.MovingTime,
+		ElapsedTime:        activity.ElapsedTime,
+		Distance:           activity.Distance,
+		TotalElevationGain: activity.TotalElevationGain,
+		AverageSpeed:       activity.AverageSpeed,
+		MaxSpeed:           activity.MaxSpeed,
+		GearId:             activity.GearId,
+	}
+}
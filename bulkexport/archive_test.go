@@ -0,0 +1,162 @@
+package bulkexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+const testActivitiesCsv = `Activity ID,Activity Date,Activity Name,Activity Type,Elapsed Time,Distance,Filename,Elapsed Time,Moving Time,Distance,Max Speed,Average Speed,Elevation Gain
+111,"Jan 2, 2018, 3:04:05 PM",Morning Ride,Ride,3600,"30.5",activities/111.gpx.gz,3600.0,3400.0,30500.0,15.5,,120.0
+222,"Feb 3, 2018, 7:00:00 AM",Lunch Run,Run,1800,5.0,activities/222.tcx,1800.0,1700.0,5000.0,4.2,2.9,20.0
+333,"Mar 4, 2018, 8:00:00 AM",Treadmill,Run,600,2.0,,600.0,,2000.0,,,
+444,"Mar 5, 2018, 8:00:00 AM",Garmin Ride,Ride,600,2.0,activities/444.fit.gz,600.0,600.0,2000.0,,,
+`
+
+const testGpx = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" xmlns="http://www.topografix.com/GPX/1/1" xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+ <trk><trkseg>
+  <trkpt lat="37.0" lon="-122.0"><ele>10.0</ele><time>2018-01-02T15:04:05Z</time>
+   <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>120</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions></trkpt>
+  <trkpt lat="37.001" lon="-122.0"><ele>12.0</ele><time>2018-01-02T15:04:15Z</time>
+   <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>125</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions></trkpt>
+ </trkseg></trk>
+</gpx>`
+
+const testTcx = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2">
+ <Activities><Activity Sport="Running"><Lap StartTime="2018-02-03T07:00:00Z"><Track>
+  <Trackpoint><Time>2018-02-03T07:00:00Z</Time><DistanceMeters>0.0</DistanceMeters><HeartRateBpm><Value>140</Value></HeartRateBpm></Trackpoint>
+  <Trackpoint><Time>2018-02-03T07:00:05Z</Time><DistanceMeters>15.5</DistanceMeters><HeartRateBpm><Value>145</Value></HeartRateBpm></Trackpoint>
+ </Track></Lap></Activity></Activities>
+</TrainingCenterDatabase>`
+
+func TestImportReader(t *testing.T) {
+	archive := testArchive(t, map[string][]byte{
+		"activities.csv":        []byte(testActivitiesCsv),
+		"activities/111.gpx.gz": gzipped(t, testGpx),
+		"activities/222.tcx":    []byte(testTcx),
+		"activities/444.fit.gz": gzipped(t, "not a FIT file"),
+		"media/ignored.jpg":     []byte("jpeg"),
+	})
+
+	result, err := ImportReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("Unexpected error for ImportReader. error=%s", err)
+	}
+
+	if len(result.Activities) != 4 {
+		t.Fatalf("Expected 4 activities but got %d", len(result.Activities))
+	}
+	if expected := []string{"activities/444.fit.gz: invalid FIT file"}; !reflect.DeepEqual(expected, result.Skipped) {
+		t.Fatalf("Skipped was not as expected. expected=%v, actual=%v", expected, result.Skipped)
+	}
+
+	ride := result.Activities[0]
+	expectedRide := &model.Activity{
+		Id:                 111,
+		Name:               "Morning Ride",
+		Type:               "Ride",
+		StartDate:          time.Date(2018, 1, 2, 15, 4, 5, 0, time.UTC),
+		StartDateLocal:     time.Date(2018, 1, 2, 15, 4, 5, 0, time.UTC),
+		MovingTime:         3400,
+		ElapsedTime:        3600,
+		Distance:           30500,
+		TotalElevationGain: 120,
+		AverageSpeed:       float32(30500) / 3400,
+		MaxSpeed:           15.5,
+	}
+	if !reflect.DeepEqual(expectedRide, ride.Activity) {
+		t.Fatalf("Ride was not as expected. expected=%+v, actual=%+v", expectedRide, ride.Activity)
+	}
+	if ride.Streams == nil || !reflect.DeepEqual(ride.Streams.Time, []uint32{0, 10}) ||
+		!reflect.DeepEqual(ride.Streams.Heartrate, []float32{120, 125}) ||
+		!reflect.DeepEqual(ride.Streams.Altitude, []float32{10, 12}) ||
+		len(ride.Streams.LatLng) != 2 {
+		t.Fatalf("Ride streams were not as expected. streams=%+v", ride.Streams)
+	}
+	// 0.001 degrees of latitude is about 111 meters
+	if distance := ride.Streams.Distance[1]; distance < 110 || distance > 112 {
+		t.Fatalf("Expected a distance of about 111m but got %f", distance)
+	}
+
+	run := result.Activities[1]
+	if run.Activity.Distance != 5000 || run.Activity.AverageSpeed != 2.9 {
+		t.Fatalf("Run was not as expected. run=%+v", run.Activity)
+	}
+	expectedStreams := &model.Streams{
+		ActivityId:     222,
+		Time:           []uint32{0, 5},
+		Distance:       []float32{0, 15.5},
+		VelocitySmooth: []float32{0, 3.1},
+		Heartrate:      []float32{140, 145},
+		Moving:         []bool{false, true},
+	}
+	if !reflect.DeepEqual(expectedStreams, run.Streams) {
+		t.Fatalf("Run streams were not as expected. expected=%+v, actual=%+v", expectedStreams, run.Streams)
+	}
+
+	treadmill := result.Activities[2]
+	if treadmill.Streams != nil || treadmill.Activity.MovingTime != 600 {
+		t.Fatalf("Treadmill run was not as expected. activity=%+v, streams=%+v", treadmill.Activity, treadmill.Streams)
+	}
+
+	summary := ride.Summary()
+	if summary.Id != 111 || summary.Distance != 30500 || summary.Type != "Ride" {
+		t.Fatalf("Summary was not as expected. summary=%+v", summary)
+	}
+}
+
+func TestImportReader_KilometerDistance(t *testing.T) {
+	archive := testArchive(t, map[string][]byte{
+		"export_123/activities.csv": []byte("Activity ID,Activity Date,Activity Name,Activity Type,Elapsed Time,Distance\n" +
+			`555,"Apr 5, 2018, 9:00:00 AM",Walk,Walk,1000,"1,234.5"` + "\n"),
+	})
+
+	result, err := ImportReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("Unexpected error for ImportReader. error=%s", err)
+	}
+	if len(result.Activities) != 1 || result.Activities[0].Activity.Distance != 1234500 {
+		t.Fatalf("Activities were not as expected. activities=%+v", result.Activities)
+	}
+}
+
+func TestImportReader_NoActivitiesCsv(t *testing.T) {
+	archive := testArchive(t, map[string][]byte{"profile.csv": []byte("")})
+
+	if _, err := ImportReader(bytes.NewReader(archive), int64(len(archive))); err == nil {
+		t.Fatalf("Expected an error for an export without activities.csv")
+	}
+}
+
+func testArchive(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, body := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Unexpected error creating zip. error=%s", err)
+		}
+		file.Write(body)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Unexpected error creating zip. error=%s", err)
+	}
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, body string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write([]byte(body))
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Unexpected error gzipping. error=%s", err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,150 @@
+package bulkexport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// Layout of Activity Date, which is in UTC
+const activityDateLayout = "Jan 2, 2006, 3:04:05 PM"
+
+// A row of activities.csv, along with the track file it refers to.
+type csvActivity struct {
+	activity *model.Activity
+	filename string // Relative to the root of the archive; empty if there is no track file
+}
+
+// Parse activities.csv. Columns are found by header name. Some names appear twice, first in the
+// athlete's display units and again later in meters and seconds; the later column is used when present.
+func parseActivitiesCsv(r io.Reader) ([]*csvActivity, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading activities.csv header: %s", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	counts := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		columns[name] = i
+		counts[name]++
+	}
+
+	if _, found := columns["Activity ID"]; !found {
+		return nil, fmt.Errorf("activities.csv has no Activity ID column")
+	}
+
+	activities := make([]*csvActivity, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading activities.csv line %d: %s", line, err)
+		}
+
+		row := &csvRow{record: record, columns: columns}
+		activity, err := row.activity(counts["Distance"] > 1)
+		if err != nil {
+			return nil, fmt.Errorf("activities.csv line %d: %s", line, err)
+		}
+		activities = append(activities, &csvActivity{activity: activity, filename: row.get("Filename")})
+	}
+
+	return activities, nil
+}
+
+type csvRow struct {
+	record  []string
+	columns map[string]int
+}
+
+func (r *csvRow) get(column string) string {
+	i, found := r.columns[column]
+	if !found || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r *csvRow) float(column string) (float32, error) {
+	value := r.get(column)
+	if value == "" {
+		return 0, nil
+	}
+
+	f, err := strconv.ParseFloat(strings.Replace(value, ",", "", -1), 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", column, value)
+	}
+	return float32(f), nil
+}
+
+func (r *csvRow) seconds(column string) (uint32, error) {
+	f, err := r.float(column)
+	return uint32(f + 0.5), err
+}
+
+// Convert the row to an activity. Without a metric Distance column, the only one is in kilometers.
+func (r *csvRow) activity(metricDistance bool) (*model.Activity, error) {
+	id, err := strconv.ParseUint(r.get("Activity ID"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Activity ID %q", r.get("Activity ID"))
+	}
+
+	startDate, err := time.Parse(activityDateLayout, r.get("Activity Date"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Activity Date %q", r.get("Activity Date"))
+	}
+
+	activity := &model.Activity{
+		Id:        model.ActivityId(id),
+		Name:      r.get("Activity Name"),
+		Type:      r.get("Activity Type"),
+		StartDate: startDate,
+		// The export has no timezone, so local time is unknown
+		StartDateLocal: startDate,
+	}
+
+	floats := []struct {
+		column string
+		value  *float32
+	}{
+		{"Distance", &activity.Distance},
+		{"Elevation Gain", &activity.TotalElevationGain},
+		{"Average Speed", &activity.AverageSpeed},
+		{"Max Speed", &activity.MaxSpeed},
+	}
+	for _, f := range floats {
+		if *f.value, err = r.float(f.column); err != nil {
+			return nil, err
+		}
+	}
+	if !metricDistance {
+		activity.Distance *= 1000
+	}
+
+	if activity.ElapsedTime, err = r.seconds("Elapsed Time"); err != nil {
+		return nil, err
+	}
+	if activity.MovingTime, err = r.seconds("Moving Time"); err != nil {
+		return nil, err
+	}
+	if activity.MovingTime == 0 {
+		activity.MovingTime = activity.ElapsedTime
+	}
+	if activity.AverageSpeed == 0 && activity.MovingTime > 0 {
+		activity.AverageSpeed = activity.Distance / float32(activity.MovingTime)
+	}
+
+	return activity, nil
+}
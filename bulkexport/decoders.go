@@ -0,0 +1,44 @@
+package bulkexport
+
+import (
+	"io"
+
+	"github.com/alecholmes/strava/fit"
+	"github.com/alecholmes/strava/gpx"
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/tcx"
+)
+
+// Decodes a track file into streams.
+type decodeFunc func(r io.Reader) (*model.Streams, error)
+
+// Decoders by track file extension, after removing any .gz
+var decoders = map[string]decodeFunc{
+	".gpx": decodeGpx,
+	".tcx": decodeTcx,
+	".fit": decodeFit,
+}
+
+func decodeGpx(r io.Reader) (*model.Streams, error) {
+	file, err := gpx.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return file.Streams, nil
+}
+
+func decodeTcx(r io.Reader) (*model.Streams, error) {
+	file, err := tcx.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return file.Streams, nil
+}
+
+func decodeFit(r io.Reader) (*model.Streams, error) {
+	file, err := fit.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return file.Streams, nil
+}
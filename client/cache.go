@@ -0,0 +1,125 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A cached response body along with the validators used to revalidate it.
+type CachedResponse struct {
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"last_modified"`
+	StoredAt     time.Time `json:"stored_at"`
+}
+
+// Stores responses by key. Caching is best effort, so failures to store are ignored.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, response *CachedResponse)
+}
+
+// How long cached responses are used without revalidating, by relative path prefix such as "/gear/".
+// The longest matching prefix wins. Responses for paths matching no prefix are always revalidated.
+type CacheTTLs map[string]time.Duration
+
+func (ttls CacheTTLs) ttl(relativePath string) time.Duration {
+	longest := -1
+	var ttl time.Duration
+	for prefix, prefixTtl := range ttls {
+		if strings.HasPrefix(relativePath, prefix) && len(prefix) > longest {
+			longest = len(prefix)
+			ttl = prefixTtl
+		}
+	}
+	return ttl
+}
+
+type memoryCache struct {
+	lock      sync.RWMutex
+	responses map[string]*CachedResponse
+}
+
+// Create a cache that holds responses in memory for the life of the process.
+func NewMemoryCache() Cache {
+	return &memoryCache{responses: make(map[string]*CachedResponse)}
+}
+
+func (c *memoryCache) Get(key string) (*CachedResponse, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	response, found := c.responses[key]
+	if !found {
+		return nil, false
+	}
+	copied := *response
+	return &copied, true
+}
+
+func (c *memoryCache) Set(key string, response *CachedResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	copied := *response
+	c.responses[key] = &copied
+}
+
+type diskCache struct {
+	dir string
+}
+
+// Create a cache that holds responses as files in dir, so they are reused across processes.
+func NewDiskCache(dir string) (Cache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &diskCache{dir: dir}, nil
+}
+
+func (c *diskCache) Get(key string) (*CachedResponse, bool) {
+	body, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var response CachedResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, false
+	}
+	return &response, true
+}
+
+func (c *diskCache) Set(key string, response *CachedResponse) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+
+	// Write then rename so concurrent readers never see a partial file
+	tempFile, err := ioutil.TempFile(c.dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(body)
+	if closeErr := tempFile.Close(); err != nil || closeErr != nil {
+		return
+	}
+	os.Rename(tempFile.Name(), c.path(key))
+}
+
+// Keys contain URLs, so are hashed to get safe file names
+func (c *diskCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+".json")
+}
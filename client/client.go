@@ -6,7 +6,18 @@ import (
 
 const Beginning = 0
 
+// File formats routes can be exported as
+type RouteFormat string
+
+const (
+	GPX = RouteFormat("gpx")
+	TCX = RouteFormat("tcx")
+)
+
 type Client interface {
+	// Get the authenticated athlete.
+	GetAthlete() (*model.Athlete, error)
+
 	// Get activity summaries. Oldest are returned first.
 	// Only activites with ids greater than the given after are included.
 	// Pass Beginning as after to get all activities.
@@ -15,14 +26,76 @@ type Client interface {
 	// Get an activity by its id.
 	GetActivity(activityId model.ActivityId) (*model.Activity, error)
 
+	// Get all recorded streams of an activity, such as time, location and power.
+	GetActivityStreams(activityId model.ActivityId) (*model.Streams, error)
+
 	// Get multiple activities by their ids, returned in the same order.
 	// Activities that could not be fetched are excluded.
 	GetActivities(activityIds []model.ActivityId) ([]*model.Activity, error)
 
 	// Get activities summaries for activities related to the given activity id.
 	GetRelatedActivitySummaries(activityId model.ActivityId) ([]*model.ActivitySummary, error)
+
+	// Get one page of a segment's leaderboard, optionally filtered. Pages start at 1.
+	// Pass a nil filter to get the overall leaderboard.
+	GetSegmentLeaderboard(segmentId model.SegmentId, filter *LeaderboardFilter, page int) (*model.SegmentLeaderboard, error)
+
+	// Get the clubs the authenticated athlete is a member of.
+	GetAthleteClubs() ([]*model.Club, error)
+
+	// Get a club by its id.
+	GetClub(clubId model.ClubId) (*model.Club, error)
+
+	// Iterate over the members of a club.
+	GetClubMembers(clubId model.ClubId) *AthleteIterator
+
+	// Iterate over the admins of a club.
+	GetClubAdmins(clubId model.ClubId) *AthleteIterator
+
+	// Iterate over summaries of recent activities by members of a club. Newest are returned first.
+	GetClubActivities(clubId model.ClubId) *ActivitySummaryIterator
+
+	// Get a piece of gear, such as a bike or shoes, by its id.
+	GetGear(gearId model.GearId) (*model.Gear, error)
+
+	// Get a route by its id.
+	GetRoute(routeId model.RouteId) (*model.Route, error)
+
+	// Iterate over the routes created by an athlete.
+	ListAthleteRoutes(athleteId model.AthleteId) *RouteIterator
+
+	// Get a route as a GPX or TCX file.
+	ExportRoute(routeId model.RouteId, format RouteFormat) ([]byte, error)
+
+	// Iterate over the athletes who gave kudos to an activity.
+	ListActivityKudoers(activityId model.ActivityId) *AthleteIterator
+
+	// Iterate over the comments on an activity. Oldest are returned first.
+	ListActivityComments(activityId model.ActivityId) *CommentIterator
+
+	// Give kudos to an activity as the authenticated athlete.
+	// Requires write access, which Strava only permits for some applications.
+	GiveKudos(activityId model.ActivityId) error
+
+	// Comment on an activity as the authenticated athlete.
+	// Requires write access, which Strava only permits for some applications.
+	CreateActivityComment(activityId model.ActivityId, text string) (*model.Comment, error)
+
+	// Delete a comment made by the authenticated athlete.
+	// Requires write access, which Strava only permits for some applications.
+	DeleteActivityComment(activityId model.ActivityId, commentId model.CommentId) error
+
+	// Get the photos of an activity, from Strava or other sources. Urls are included for the
+	// given size in pixels, if available.
+	ListActivityPhotos(activityId model.ActivityId, size int) ([]*model.Photo, error)
 }
 
 func NewClient(accessToken string) *v3Client {
 	return &v3Client{httpClient: newHttpClientImpl(stravaBaseUrl, accessToken)}
 }
+
+// Create a client that caches responses, sending conditional requests to revalidate them
+// so unchanged responses don't need to be downloaded again.
+func NewCachingClient(accessToken string, cache Cache, ttls CacheTTLs) *v3Client {
+	return &v3Client{httpClient: newCachingHttpClientImpl(stravaBaseUrl, accessToken, cache, ttls)}
+}
@@ -1,10 +1,16 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecholmes/strava/model"
 )
 
 // Internal HTTP client. Interface to allow for testing implementations.
@@ -12,17 +18,35 @@ type HttpClient interface {
 	AbsoluteUrl(relativeUrl string, params map[string]interface{}) (string, error)
 
 	Get(relativePath string, params map[string]interface{}) ([]byte, error)
+
+	// Params are sent as a form encoded body
+	Post(relativePath string, params map[string]interface{}) ([]byte, error)
+
+	Delete(relativePath string, params map[string]interface{}) ([]byte, error)
 }
 
 type httpClientImpl struct {
 	baseUrl     string
 	accessToken string
 	httpClient  *http.Client
+	cache       Cache // Caches GET responses if not nil
+	ttls        CacheTTLs
+	now         func() time.Time
+
+	athleteLock sync.Mutex
+	athleteId   model.AthleteId // Owner of the access token, once looked up for cache keys
 }
 
 // Create a new HTTP client that uses the given accessToken for authentication.
 func newHttpClientImpl(baseUrl string, accessToken string) HttpClient {
-	return &httpClientImpl{baseUrl: baseUrl, accessToken: accessToken, httpClient: http.DefaultClient}
+	return &httpClientImpl{baseUrl: baseUrl, accessToken: accessToken, httpClient: http.DefaultClient, now: time.Now}
+}
+
+// Create a new HTTP client that caches GET responses. Cached responses are revalidated with
+// conditional requests unless they are younger than the TTL for their path.
+func newCachingHttpClientImpl(baseUrl string, accessToken string, cache Cache, ttls CacheTTLs) HttpClient {
+	return &httpClientImpl{baseUrl: baseUrl, accessToken: accessToken, httpClient: http.DefaultClient,
+		cache: cache, ttls: ttls, now: time.Now}
 }
 
 func (c *httpClientImpl) AbsoluteUrl(relativePath string, params map[string]interface{}) (string, error) {
@@ -47,23 +71,155 @@ func (client *httpClientImpl) Get(relativePath string, params map[string]interfa
 	}
 
 	request, _ := http.NewRequest("GET", absUrl, nil)
+	if client.cache == nil {
+		return client.do(request)
+	}
+
+	key, err := client.cacheKey(absUrl)
+	if err != nil {
+		return nil, err
+	}
+	cached, found := client.cache.Get(key)
+	if found && client.now().Sub(cached.StoredAt) < client.ttls.ttl(relativePath) {
+		return cached.Body, nil
+	}
+
+	if found {
+		if cached.ETag != "" {
+			request.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			request.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	response, body, err := client.send(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotModified && found {
+		cached.StoredAt = client.now()
+		client.cache.Set(key, cached)
+		return cached.Body, nil
+	} else if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("Unexpected HTTP response %v", response.Status)
+	}
+
+	client.cache.Set(key, &CachedResponse{
+		Body:         body,
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+		StoredAt:     client.now(),
+	})
+
+	return body, nil
+}
+
+func (client *httpClientImpl) Post(relativePath string, params map[string]interface{}) ([]byte, error) {
+	absUrl, err := client.AbsoluteUrl(relativePath, make(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	for k, v := range params {
+		form.Add(k, fmt.Sprintf("%v", v))
+	}
+
+	request, _ := http.NewRequest("POST", absUrl, strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return client.do(request)
+}
+
+func (client *httpClientImpl) Delete(relativePath string, params map[string]interface{}) ([]byte, error) {
+	absUrl, err := client.AbsoluteUrl(relativePath, params)
+	if err != nil {
+		return nil, err
+	}
+
+	request, _ := http.NewRequest("DELETE", absUrl, nil)
+	return client.do(request)
+}
+
+// Send an authenticated request, returning the body of a successful (2xx) response.
+func (client *httpClientImpl) do(request *http.Request) ([]byte, error) {
+	response, body, err := client.send(request)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("Unexpected HTTP response %v", response.Status)
+	}
+
+	return body, nil
+}
+
+// Send an authenticated request, returning the response and its body regardless of status.
+func (client *httpClientImpl) send(request *http.Request) (*http.Response, []byte, error) {
 	request.Header.Set("Authorization", client.bearerToken())
 	request.Header.Set("Accept", "application/json")
 
 	response, err := client.httpClient.Do(request)
 	if err != nil {
-		return nil, err
-	} else if response.StatusCode != 200 {
-		return nil, fmt.Errorf("Unexpected HTTP response %v", response.Status)
+		return nil, nil, err
 	}
 	defer response.Body.Close()
 
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return body, nil
+	return response, body, nil
+}
+
+// Responses differ by athlete, so the athlete is part of the key. Access tokens are refreshed
+// every few hours, so keying by the token would throw the cache away each time.
+func (client *httpClientImpl) cacheKey(absUrl string) (string, error) {
+	athleteId, err := client.tokenAthleteId()
+	if err != nil {
+		return "", fmt.Errorf("getting athlete for cache: %s", err)
+	}
+	return fmt.Sprintf("athlete %d %s", athleteId, absUrl), nil
+}
+
+// The id of the athlete the access token belongs to. The lookup is also cached by token, so
+// it costs one request per token rather than one per process.
+func (client *httpClientImpl) tokenAthleteId() (model.AthleteId, error) {
+	client.athleteLock.Lock()
+	defer client.athleteLock.Unlock()
+
+	if client.athleteId != 0 {
+		return client.athleteId, nil
+	}
+
+	tokenKey := "token " + client.accessToken
+	if cached, found := client.cache.Get(tokenKey); found {
+		if err := json.Unmarshal(cached.Body, &client.athleteId); err == nil && client.athleteId != 0 {
+			return client.athleteId, nil
+		}
+	}
+
+	absUrl, err := client.AbsoluteUrl("/athlete", make(map[string]interface{}))
+	if err != nil {
+		return 0, err
+	}
+	request, _ := http.NewRequest("GET", absUrl, nil)
+	body, err := client.do(request)
+	if err != nil {
+		return 0, err
+	}
+
+	var athlete model.Athlete
+	if err := json.Unmarshal(body, &athlete); err != nil {
+		return 0, err
+	} else if athlete.Id == 0 {
+		return 0, fmt.Errorf("athlete has no id")
+	}
+
+	client.athleteId = athlete.Id
+	client.cache.Set(tokenKey, &CachedResponse{Body: []byte(fmt.Sprint(athlete.Id)), StoredAt: client.now()})
+	return client.athleteId, nil
 }
 
 func (client *httpClientImpl) bearerToken() string {
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHttpClientGet_ETag(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/athlete" {
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	client := newCachingHttpClientImpl(server.URL, "token", NewMemoryCache(), nil)
+
+	for i := 0; i < 2; i++ {
+		body, err := client.Get("/activities/1", make(map[string]interface{}))
+		if err != nil {
+			t.Fatalf("Unexpected error for Get. error=%s", err)
+		}
+		if string(body) != `{"id": 1}` {
+			t.Fatalf("Unexpected body %s", body)
+		}
+	}
+
+	// The second request is still made, but revalidated rather than downloaded
+	if requests != 2 {
+		t.Fatalf("Expected 2 requests but got %d", requests)
+	}
+}
+
+func TestHttpClientGet_LastModified(t *testing.T) {
+	lastModified := "Sat, 04 Oct 2014 15:07:31 GMT"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+	client := newCachingHttpClientImpl(server.URL, "token", cache, nil)
+
+	client.Get("/activities/1", make(map[string]interface{}))
+	body, err := client.Get("/activities/1", make(map[string]interface{}))
+	if err != nil || string(body) != `{"id": 1}` {
+		t.Fatalf("Unexpected response. body=%s, error=%s", body, err)
+	}
+}
+
+func TestHttpClientGet_TTL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/athlete" {
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		requests++
+		w.Write([]byte(`{"id": "b1"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2014, 10, 4, 15, 0, 0, 0, time.UTC)
+	client := newCachingHttpClientImpl(server.URL, "token", NewMemoryCache(), CacheTTLs{"/gear/": time.Hour}).(*httpClientImpl)
+	client.now = func() time.Time { return now }
+
+	client.Get("/gear/b1", make(map[string]interface{}))
+	client.Get("/gear/b1", make(map[string]interface{}))
+	if requests != 1 {
+		t.Fatalf("Expected cached response within TTL but got %d requests", requests)
+	}
+
+	now = now.Add(2 * time.Hour)
+	client.Get("/gear/b1", make(map[string]interface{}))
+	if requests != 2 {
+		t.Fatalf("Expected request after TTL expired but got %d requests", requests)
+	}
+
+	// Paths without a TTL are always requested
+	client.Get("/activities/1", make(map[string]interface{}))
+	client.Get("/activities/1", make(map[string]interface{}))
+	if requests != 4 {
+		t.Fatalf("Expected 4 requests but got %d", requests)
+	}
+}
+
+func TestHttpClientGet_ErrorNotCached(t *testing.T) {
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/athlete" {
+			w.Write([]byte(`{"id": 1}`))
+			return
+		}
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newCachingHttpClientImpl(server.URL, "token", NewMemoryCache(), CacheTTLs{"/": time.Hour})
+
+	if _, err := client.Get("/activities/1", make(map[string]interface{})); err == nil {
+		t.Fatalf("Expected error for failed request")
+	}
+
+	fail = false
+	if body, err := client.Get("/activities/1", make(map[string]interface{})); err != nil || string(body) != `{}` {
+		t.Fatalf("Unexpected response. body=%s, error=%s", body, err)
+	}
+}
+
+func TestHttpClientGet_KeyedByAthlete(t *testing.T) {
+	athleteRequests, gearRequests := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/athlete" {
+			athleteRequests++
+			if r.Header.Get("Authorization") == "Bearer other" {
+				w.Write([]byte(`{"id": 2}`))
+			} else {
+				w.Write([]byte(`{"id": 1}`))
+			}
+			return
+		}
+		gearRequests++
+		w.Write([]byte(`{"id": "b1"}`))
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+	ttls := CacheTTLs{"/gear/": time.Hour}
+
+	newCachingHttpClientImpl(server.URL, "token", cache, ttls).Get("/gear/b1", make(map[string]interface{}))
+
+	// A refreshed token for the same athlete reuses the cached response
+	refreshed := newCachingHttpClientImpl(server.URL, "refreshed", cache, ttls)
+	refreshed.Get("/gear/b1", make(map[string]interface{}))
+	refreshed.Get("/gear/b1", make(map[string]interface{}))
+	if athleteRequests != 2 || gearRequests != 1 {
+		t.Fatalf("Expected 2 athlete requests and 1 gear request but got %d and %d", athleteRequests, gearRequests)
+	}
+
+	// The athlete of a token is only looked up once
+	newCachingHttpClientImpl(server.URL, "refreshed", cache, ttls).Get("/gear/b1", make(map[string]interface{}))
+	if athleteRequests != 2 {
+		t.Fatalf("Expected 2 athlete requests but got %d", athleteRequests)
+	}
+
+	// Another athlete's responses are cached separately
+	newCachingHttpClientImpl(server.URL, "other", cache, ttls).Get("/gear/b1", make(map[string]interface{}))
+	if athleteRequests != 3 || gearRequests != 2 {
+		t.Fatalf("Expected 3 athlete requests and 2 gear requests but got %d and %d", athleteRequests, gearRequests)
+	}
+}
+
+func TestCacheTTLs_LongestPrefix(t *testing.T) {
+	ttls := CacheTTLs{"/activities/": time.Minute, "/activities/1/streams": time.Hour}
+
+	if ttl := ttls.ttl("/activities/1/streams"); ttl != time.Hour {
+		t.Fatalf("Expected TTL of an hour but got %s", ttl)
+	}
+	if ttl := ttls.ttl("/activities/1"); ttl != time.Minute {
+		t.Fatalf("Expected TTL of a minute but got %s", ttl)
+	}
+	if ttl := ttls.ttl("/athlete"); ttl != 0 {
+		t.Fatalf("Expected no TTL but got %s", ttl)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache-test")
+	if err != nil {
+		t.Fatalf("Could not create temp dir. error=%s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error for NewDiskCache. error=%s", err)
+	}
+
+	if _, found := cache.Get("token http://test/athlete"); found {
+		t.Fatalf("Expected empty cache")
+	}
+
+	response := CachedResponse{Body: []byte(`{}`), ETag: `"v1"`, StoredAt: time.Date(2014, 10, 4, 15, 0, 0, 0, time.UTC)}
+	cache.Set("token http://test/athlete", &response)
+
+	// A new cache on the same directory sees the response
+	reopened, _ := NewDiskCache(dir)
+	cached, found := reopened.Get("token http://test/athlete")
+	if !found || string(cached.Body) != `{}` || cached.ETag != `"v1"` || !cached.StoredAt.Equal(response.StoredAt) {
+		t.Fatalf("Cached response was not as expected. expected=%v, actual=%v", response, cached)
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHttpClientPost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || r.URL.Path != "/comments" || string(body) != "text=hi+there" {
+			t.Errorf("Unexpected request. method=%s, path=%s, body=%s", r.Method, r.URL.Path, body)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Unexpected Authorization header %s", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	client := newHttpClientImpl(server.URL, "token")
+	body, err := client.Post("/comments", map[string]interface{}{"text": "hi there"})
+	if err != nil {
+		t.Fatalf("Unexpected error for Post. error=%s", err)
+	}
+	if string(body) != `{"id": 1}` {
+		t.Fatalf("Unexpected body %s", body)
+	}
+}
+
+func TestHttpClientDelete_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := newHttpClientImpl(server.URL, "token")
+	if _, err := client.Delete("/comments/1", make(map[string]interface{})); err == nil {
+		t.Fatalf("Expected error for forbidden response")
+	}
+}
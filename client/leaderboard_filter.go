@@ -0,0 +1,88 @@
+package client
+
+import (
+	"github.com/alecholmes/strava/model"
+)
+
+type Gender string
+type AgeGroup string
+type WeightClass string
+type DateRange string
+
+const (
+	AnyGender = Gender("")
+	Male      = Gender("M")
+	Female    = Gender("F")
+)
+
+const (
+	AnyAge    = AgeGroup("")
+	Age0To24  = AgeGroup("0_24")
+	Age25To34 = AgeGroup("25_34")
+	Age35To44 = AgeGroup("35_44")
+	Age45To54 = AgeGroup("45_54")
+	Age55To64 = AgeGroup("55_64")
+	Age65Plus = AgeGroup("65_plus")
+)
+
+// Weight classes are in pounds or kilograms, depending on the class.
+const (
+	AnyWeight        = WeightClass("")
+	Weight0To124Lb   = WeightClass("0_124")
+	Weight125To149Lb = WeightClass("125_149")
+	Weight150To164Lb = WeightClass("150_164")
+	Weight165To179Lb = WeightClass("165_179")
+	Weight180To199Lb = WeightClass("180_199")
+	Weight200PlusLb  = WeightClass("200_plus")
+	Weight0To54Kg    = WeightClass("0_54")
+	Weight55To64Kg   = WeightClass("55_64")
+	Weight65To74Kg   = WeightClass("65_74")
+	Weight75To84Kg   = WeightClass("75_84")
+	Weight85To94Kg   = WeightClass("85_94")
+	Weight95PlusKg   = WeightClass("95_plus")
+)
+
+const (
+	AllTime   = DateRange("")
+	ThisYear  = DateRange("this_year")
+	ThisMonth = DateRange("this_month")
+	ThisWeek  = DateRange("this_week")
+	Today     = DateRange("today")
+)
+
+// Restricts the efforts included in a segment leaderboard.
+// Zero values mean no restriction.
+type LeaderboardFilter struct {
+	Gender      Gender
+	AgeGroup    AgeGroup
+	WeightClass WeightClass
+	Following   bool // Only athletes the authenticated athlete follows
+	ClubId      model.ClubId
+	DateRange   DateRange
+}
+
+// Query params for the filter, added to the given params.
+func (f *LeaderboardFilter) addParams(params map[string]interface{}) {
+	if f == nil {
+		return
+	}
+
+	if f.Gender != AnyGender {
+		params["gender"] = f.Gender
+	}
+	if f.AgeGroup != AnyAge {
+		params["age_group"] = f.AgeGroup
+	}
+	if f.WeightClass != AnyWeight {
+		params["weight_class"] = f.WeightClass
+	}
+	if f.Following {
+		params["following"] = true
+	}
+	if f.ClubId != 0 {
+		params["club_id"] = f.ClubId
+	}
+	if f.DateRange != AllTime {
+		params["date_range"] = f.DateRange
+	}
+}
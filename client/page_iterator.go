@@ -0,0 +1,157 @@
+package client
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// Page size used by list endpoints that are iterated over
+const listPageSize = 100
+
+// Iterates over a paged list endpoint, fetching one page per call.
+// Iteration ends once the endpoint returns an empty page.
+type pageIterator struct {
+	httpClient HttpClient
+	url        string
+	page       int
+	done       bool
+}
+
+func newPageIterator(httpClient HttpClient, url string) *pageIterator {
+	return &pageIterator{httpClient: httpClient, url: url}
+}
+
+// Unmarshal the next page into the given slice pointer.
+// Returns false, without modifying the slice, if there are no more pages.
+func (it *pageIterator) next(page interface{}) (bool, error) {
+	if it.done {
+		return false, nil
+	}
+
+	body, err := it.httpClient.Get(it.url, map[string]interface{}{"per_page": listPageSize, "page": it.page + 1})
+	if err != nil {
+		return false, err
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		return false, err
+	}
+	if len(items) == 0 {
+		it.done = true
+		return false, nil
+	}
+
+	if err := json.Unmarshal(body, page); err != nil {
+		return false, err
+	}
+	it.page++
+
+	return true, nil
+}
+
+// Append all remaining pages to the given slice pointer.
+func (it *pageIterator) all(all interface{}) error {
+	slice := reflect.ValueOf(all).Elem()
+	for {
+		page := reflect.New(slice.Type())
+		if more, err := it.next(page.Interface()); err != nil || !more {
+			return err
+		}
+		slice.Set(reflect.AppendSlice(slice, page.Elem()))
+	}
+}
+
+// Iterates over pages of athletes.
+type AthleteIterator struct {
+	pages *pageIterator
+}
+
+// Get the next page of athletes. An empty page means there are no more.
+func (it *AthleteIterator) Next() ([]*model.Athlete, error) {
+	athletes := make([]*model.Athlete, 0)
+	if _, err := it.pages.next(&athletes); err != nil {
+		return nil, err
+	}
+	return athletes, nil
+}
+
+// Get all remaining athletes.
+func (it *AthleteIterator) All() ([]*model.Athlete, error) {
+	all := make([]*model.Athlete, 0)
+	if err := it.pages.all(&all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// Iterates over pages of activity summaries.
+type ActivitySummaryIterator struct {
+	pages *pageIterator
+}
+
+// Get the next page of activity summaries. An empty page means there are no more.
+func (it *ActivitySummaryIterator) Next() ([]*model.ActivitySummary, error) {
+	summaries := make([]*model.ActivitySummary, 0)
+	if _, err := it.pages.next(&summaries); err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// Get all remaining activity summaries.
+func (it *ActivitySummaryIterator) All() ([]*model.ActivitySummary, error) {
+	all := make([]*model.ActivitySummary, 0)
+	if err := it.pages.all(&all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// Iterates over pages of routes.
+type RouteIterator struct {
+	pages *pageIterator
+}
+
+// Get the next page of routes. An empty page means there are no more.
+func (it *RouteIterator) Next() ([]*model.Route, error) {
+	routes := make([]*model.Route, 0)
+	if _, err := it.pages.next(&routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// Get all remaining routes.
+func (it *RouteIterator) All() ([]*model.Route, error) {
+	all := make([]*model.Route, 0)
+	if err := it.pages.all(&all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// Iterates over pages of comments.
+type CommentIterator struct {
+	pages *pageIterator
+}
+
+// Get the next page of comments. An empty page means there are no more.
+func (it *CommentIterator) Next() ([]*model.Comment, error) {
+	comments := make([]*model.Comment, 0)
+	if _, err := it.pages.next(&comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Get all remaining comments.
+func (it *CommentIterator) All() ([]*model.Comment, error) {
+	all := make([]*model.Comment, 0)
+	if err := it.pages.all(&all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
@@ -15,7 +15,9 @@ func expectedBody(body []byte) bodyOrError {
 
 type testHttpClient struct {
 	HttpClient
-	Gets map[string]bodyOrError
+	Gets    map[string]bodyOrError
+	Posts   map[string]bodyOrError
+	Deletes map[string]bodyOrError
 }
 
 // Assert testHttpClient implements HttpClient
@@ -23,7 +25,7 @@ var _ HttpClient = &testHttpClient{}
 
 func newTestHttpClient() *testHttpClient {
 	client := newHttpClientImpl("http://test", "fake-access-token")
-	return &testHttpClient{client, make(map[string]bodyOrError)}
+	return &testHttpClient{client, make(map[string]bodyOrError), make(map[string]bodyOrError), make(map[string]bodyOrError)}
 }
 
 func (client *testHttpClient) Get(relativePath string, params map[string]interface{}) ([]byte, error) {
@@ -39,3 +41,32 @@ func (client *testHttpClient) Get(relativePath string, params map[string]interfa
 
 	return bodyOrError.Body, bodyOrError.Error
 }
+
+// Posts are keyed by the absolute URL including params, as though params were sent in the query string
+func (client *testHttpClient) Post(relativePath string, params map[string]interface{}) ([]byte, error) {
+	absoluteUrl, err := client.AbsoluteUrl(relativePath, params)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyOrError, ok := client.Posts[absoluteUrl]
+	if !ok {
+		panic(fmt.Sprintf("Posts did not contain %s", absoluteUrl))
+	}
+
+	return bodyOrError.Body, bodyOrError.Error
+}
+
+func (client *testHttpClient) Delete(relativePath string, params map[string]interface{}) ([]byte, error) {
+	absoluteUrl, err := client.AbsoluteUrl(relativePath, params)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyOrError, ok := client.Deletes[absoluteUrl]
+	if !ok {
+		panic(fmt.Sprintf("Deletes did not contain %s", absoluteUrl))
+	}
+
+	return bodyOrError.Body, bodyOrError.Error
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"sync"
 
 	"github.com/alecholmes/strava/model"
@@ -11,9 +12,18 @@ import (
 
 const stravaBaseUrl = "https://www.strava.com/api/v3"
 
+const athleteUrl = "/athlete"
+
 const activitySummariesUrl = "/athlete/activities"
 const activitySummariesPageSize = 100
 
+const segmentLeaderboardPageSize = 50
+
+const athleteClubsUrl = "/athlete/clubs"
+
+// All stream types, requested together
+const streamKeys = "time,latlng,distance,altitude,velocity_smooth,heartrate,cadence,watts,temp,moving,grade_smooth"
+
 // Number of concurrent get requests allowed
 const getActivitiesPoolSize = 10
 
@@ -26,6 +36,20 @@ type v3Client struct {
 // Assert v3Client implements Client
 var _ Client = &v3Client{}
 
+func (c *v3Client) GetAthlete() (*model.Athlete, error) {
+	body, err := c.httpClient.Get(athleteUrl, make(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	var athlete model.Athlete
+	if err := json.Unmarshal(body, &athlete); err != nil {
+		return nil, err
+	}
+
+	return &athlete, nil
+}
+
 func (c *v3Client) GetActivitySummaries(after model.ActivityId) ([]*model.ActivitySummary, error) {
 	return c.getActivitySummaries(activitySummariesUrl, after)
 }
@@ -44,6 +68,46 @@ func (c *v3Client) GetActivity(activityId model.ActivityId) (*model.Activity, er
 	return &activity, nil
 }
 
+func (c *v3Client) GetActivityStreams(activityId model.ActivityId) (*model.Streams, error) {
+	body, err := c.httpClient.Get(activityStreamsUrl(activityId), map[string]interface{}{"keys": streamKeys, "key_by_type": true})
+	if err != nil {
+		return nil, err
+	}
+
+	// Keyed by type, each stream's values are in data
+	var keyed struct {
+		Time           struct{ Data []uint32 }     `json:"time"`
+		LatLng         struct{ Data [][2]float64 } `json:"latlng"`
+		Distance       struct{ Data []float32 }    `json:"distance"`
+		Altitude       struct{ Data []float32 }    `json:"altitude"`
+		VelocitySmooth struct{ Data []float32 }    `json:"velocity_smooth"`
+		Heartrate      struct{ Data []float32 }    `json:"heartrate"`
+		Cadence        struct{ Data []float32 }    `json:"cadence"`
+		Watts          struct{ Data []float32 }    `json:"watts"`
+		Temp           struct{ Data []float32 }    `json:"temp"`
+		Moving         struct{ Data []bool }       `json:"moving"`
+		GradeSmooth    struct{ Data []float32 }    `json:"grade_smooth"`
+	}
+	if err := json.Unmarshal(body, &keyed); err != nil {
+		return nil, err
+	}
+
+	return &model.Streams{
+		ActivityId:     activityId,
+		Time:           keyed.Time.Data,
+		LatLng:         keyed.LatLng.Data,
+		Distance:       keyed.Distance.Data,
+		Altitude:       keyed.Altitude.Data,
+		VelocitySmooth: keyed.VelocitySmooth.Data,
+		Heartrate:      keyed.Heartrate.Data,
+		Cadence:        keyed.Cadence.Data,
+		Watts:          keyed.Watts.Data,
+		Temp:           keyed.Temp.Data,
+		Moving:         keyed.Moving.Data,
+		GradeSmooth:    keyed.GradeSmooth.Data,
+	}, nil
+}
+
 func (c *v3Client) GetActivities(activityIds []model.ActivityId) ([]*model.Activity, error) {
 	var wg sync.WaitGroup
 
@@ -96,6 +160,161 @@ func (c *v3Client) GetRelatedActivitySummaries(activityId model.ActivityId) ([]*
 	return c.getActivitySummaries(relatedActivitySummariesUrl(activityId), Beginning)
 }
 
+func (c *v3Client) GetSegmentLeaderboard(segmentId model.SegmentId, filter *LeaderboardFilter, page int) (*model.SegmentLeaderboard, error) {
+	if page <= 0 {
+		return nil, errors.New("page must be positive")
+	}
+
+	params := map[string]interface{}{"per_page": segmentLeaderboardPageSize, "page": page}
+	filter.addParams(params)
+
+	body, err := c.httpClient.Get(segmentLeaderboardUrl(segmentId), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var leaderboard model.SegmentLeaderboard
+	if err := json.Unmarshal(body, &leaderboard); err != nil {
+		return nil, err
+	}
+
+	return &leaderboard, nil
+}
+
+func (c *v3Client) GetAthleteClubs() ([]*model.Club, error) {
+	body, err := c.httpClient.Get(athleteClubsUrl, make(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	clubs := make([]*model.Club, 0)
+	if err := json.Unmarshal(body, &clubs); err != nil {
+		return nil, err
+	}
+
+	return clubs, nil
+}
+
+func (c *v3Client) GetClub(clubId model.ClubId) (*model.Club, error) {
+	body, err := c.httpClient.Get(clubUrl(clubId), make(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	var club model.Club
+	if err := json.Unmarshal(body, &club); err != nil {
+		return nil, err
+	}
+
+	return &club, nil
+}
+
+func (c *v3Client) GetClubMembers(clubId model.ClubId) *AthleteIterator {
+	return &AthleteIterator{newPageIterator(c.httpClient, clubMembersUrl(clubId))}
+}
+
+func (c *v3Client) GetClubAdmins(clubId model.ClubId) *AthleteIterator {
+	return &AthleteIterator{newPageIterator(c.httpClient, clubAdminsUrl(clubId))}
+}
+
+func (c *v3Client) GetClubActivities(clubId model.ClubId) *ActivitySummaryIterator {
+	return &ActivitySummaryIterator{newPageIterator(c.httpClient, clubActivitiesUrl(clubId))}
+}
+
+func (c *v3Client) GetGear(gearId model.GearId) (*model.Gear, error) {
+	body, err := c.httpClient.Get(gearUrl(gearId), make(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	var gear model.Gear
+	if err := json.Unmarshal(body, &gear); err != nil {
+		return nil, err
+	}
+
+	return &gear, nil
+}
+
+func (c *v3Client) GetRoute(routeId model.RouteId) (*model.Route, error) {
+	body, err := c.httpClient.Get(routeUrl(routeId), make(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	var route model.Route
+	if err := json.Unmarshal(body, &route); err != nil {
+		return nil, err
+	}
+
+	return &route, nil
+}
+
+func (c *v3Client) ListAthleteRoutes(athleteId model.AthleteId) *RouteIterator {
+	return &RouteIterator{newPageIterator(c.httpClient, athleteRoutesUrl(athleteId))}
+}
+
+func (c *v3Client) ExportRoute(routeId model.RouteId, format RouteFormat) ([]byte, error) {
+	if format != GPX && format != TCX {
+		return nil, fmt.Errorf("unsupported route format %s", format)
+	}
+
+	return c.httpClient.Get(routeExportUrl(routeId, format), make(map[string]interface{}))
+}
+
+func (c *v3Client) ListActivityKudoers(activityId model.ActivityId) *AthleteIterator {
+	return &AthleteIterator{newPageIterator(c.httpClient, activityKudosUrl(activityId))}
+}
+
+func (c *v3Client) ListActivityComments(activityId model.ActivityId) *CommentIterator {
+	return &CommentIterator{newPageIterator(c.httpClient, activityCommentsUrl(activityId))}
+}
+
+func (c *v3Client) GiveKudos(activityId model.ActivityId) error {
+	_, err := c.httpClient.Post(activityKudosUrl(activityId), make(map[string]interface{}))
+	return err
+}
+
+func (c *v3Client) CreateActivityComment(activityId model.ActivityId, text string) (*model.Comment, error) {
+	if text == "" {
+		return nil, errors.New("comment text must not be empty")
+	}
+
+	body, err := c.httpClient.Post(activityCommentsUrl(activityId), map[string]interface{}{"text": text})
+	if err != nil {
+		return nil, err
+	}
+
+	var comment model.Comment
+	if err := json.Unmarshal(body, &comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (c *v3Client) DeleteActivityComment(activityId model.ActivityId, commentId model.CommentId) error {
+	_, err := c.httpClient.Delete(activityCommentUrl(activityId, commentId), make(map[string]interface{}))
+	return err
+}
+
+func (c *v3Client) ListActivityPhotos(activityId model.ActivityId, size int) ([]*model.Photo, error) {
+	if size <= 0 {
+		return nil, errors.New("size must be positive")
+	}
+
+	body, err := c.httpClient.Get(activityPhotosUrl(activityId), map[string]interface{}{"photo_sources": true, "size": size})
+	if err != nil {
+		return nil, err
+	}
+
+	photos := make([]*model.Photo, 0)
+	if err := json.Unmarshal(body, &photos); err != nil {
+		return nil, err
+	}
+
+	return photos, nil
+}
+
 func activityUrl(activityId model.ActivityId) string {
 	return fmt.Sprintf("/activities/%d", activityId)
 }
@@ -104,6 +323,62 @@ func relatedActivitySummariesUrl(activityId model.ActivityId) string {
 	return fmt.Sprintf("%s/related", activityUrl(activityId))
 }
 
+func segmentLeaderboardUrl(segmentId model.SegmentId) string {
+	return fmt.Sprintf("/segments/%d/leaderboard", segmentId)
+}
+
+func activityStreamsUrl(activityId model.ActivityId) string {
+	return fmt.Sprintf("%s/streams", activityUrl(activityId))
+}
+
+func activityKudosUrl(activityId model.ActivityId) string {
+	return fmt.Sprintf("%s/kudos", activityUrl(activityId))
+}
+
+func activityCommentsUrl(activityId model.ActivityId) string {
+	return fmt.Sprintf("%s/comments", activityUrl(activityId))
+}
+
+func activityCommentUrl(activityId model.ActivityId, commentId model.CommentId) string {
+	return fmt.Sprintf("%s/%d", activityCommentsUrl(activityId), commentId)
+}
+
+func activityPhotosUrl(activityId model.ActivityId) string {
+	return fmt.Sprintf("%s/photos", activityUrl(activityId))
+}
+
+func clubUrl(clubId model.ClubId) string {
+	return fmt.Sprintf("/clubs/%d", clubId)
+}
+
+func clubMembersUrl(clubId model.ClubId) string {
+	return fmt.Sprintf("%s/members", clubUrl(clubId))
+}
+
+func clubAdminsUrl(clubId model.ClubId) string {
+	return fmt.Sprintf("%s/admins", clubUrl(clubId))
+}
+
+func clubActivitiesUrl(clubId model.ClubId) string {
+	return fmt.Sprintf("%s/activities", clubUrl(clubId))
+}
+
+func gearUrl(gearId model.GearId) string {
+	return fmt.Sprintf("/gear/%s", url.PathEscape(string(gearId)))
+}
+
+func routeUrl(routeId model.RouteId) string {
+	return fmt.Sprintf("/routes/%d", routeId)
+}
+
+func routeExportUrl(routeId model.RouteId, format RouteFormat) string {
+	return fmt.Sprintf("%s/export_%s", routeUrl(routeId), format)
+}
+
+func athleteRoutesUrl(athleteId model.AthleteId) string {
+	return fmt.Sprintf("/athletes/%d/routes", athleteId)
+}
+
 func (c *v3Client) getActivitySummaries(url string, after model.ActivityId) ([]*model.ActivitySummary, error) {
 	allActivities := make([]*model.ActivitySummary, 0)
 	complete := false
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestExportRoute(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	gpx := `<?xml version="1.0" encoding="UTF-8"?><gpx version="1.1"></gpx>`
+	tcx := `<?xml version="1.0" encoding="UTF-8"?><TrainingCenterDatabase></TrainingCenterDatabase>`
+	rawClient.Gets[noParamsUrl(rawClient, routeExportUrl(model.RouteId(12), GPX), t)] = expectedBody([]byte(gpx))
+	rawClient.Gets[noParamsUrl(rawClient, routeExportUrl(model.RouteId(12), TCX), t)] = expectedBody([]byte(tcx))
+
+	body, err := client.ExportRoute(model.RouteId(12), GPX)
+	if err != nil {
+		t.Fatalf("Unexpected error for ExportRoute. error=%s", err)
+	}
+	if string(body) != gpx {
+		t.Fatalf("GPX export was not as expected. expected=%s, actual=%s", gpx, body)
+	}
+
+	body, err = client.ExportRoute(model.RouteId(12), TCX)
+	if err != nil {
+		t.Fatalf("Unexpected error for ExportRoute. error=%s", err)
+	}
+	if string(body) != tcx {
+		t.Fatalf("TCX export was not as expected. expected=%s, actual=%s", tcx, body)
+	}
+}
+
+func TestExportRoute_UnsupportedFormat(t *testing.T) {
+	client, _ := newTestClient()
+
+	if _, err := client.ExportRoute(model.RouteId(12), RouteFormat("kml")); err == nil {
+		t.Fatalf("Expected error for unsupported format")
+	}
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestGetActivityStreams(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	url, _ := rawClient.AbsoluteUrl(activityStreamsUrl(model.ActivityId(123)), map[string]interface{}{"keys": streamKeys, "key_by_type": true})
+	rawClient.Gets[url] = expectedBody([]byte(activityStreamsJson))
+
+	streams, err := client.GetActivityStreams(model.ActivityId(123))
+	if err != nil {
+		t.Fatalf("Unexpected error for GetActivityStreams. error=%s", err)
+	}
+
+	expected := model.Streams{
+		ActivityId: 123,
+		Time:       []uint32{0, 1, 3},
+		LatLng:     [][2]float64{{37.8331, -122.4834}, {37.8332, -122.4835}, {37.8334, -122.4837}},
+		Distance:   []float32{0, 4.2, 12.5},
+		Watts:      []float32{0, 210, 0},
+		Moving:     []bool{false, true, true},
+	}
+	if !reflect.DeepEqual(&expected, streams) {
+		t.Fatalf("Streams were not the same. expected=%v, actual=%v", &expected, streams)
+	}
+	if streams.Len() != 3 {
+		t.Fatalf("Expected 3 samples but got %d", streams.Len())
+	}
+}
+
+// This should really be in a file
+const activityStreamsJson = `
+{
+    "time": {"data": [0, 1, 3], "series_type": "distance", "original_size": 3, "resolution": "high"},
+    "latlng": {"data": [[37.8331, -122.4834], [37.8332, -122.4835], [37.8334, -122.4837]], "series_type": "distance", "original_size": 3, "resolution": "high"},
+    "distance": {"data": [0.0, 4.2, 12.5], "series_type": "distance", "original_size": 3, "resolution": "high"},
+    "watts": {"data": [null, 210, null], "series_type": "distance", "original_size": 3, "resolution": "high"},
+    "moving": {"data": [false, true, true], "series_type": "distance", "original_size": 3, "resolution": "high"}
+}
+`
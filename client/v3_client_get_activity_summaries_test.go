@@ -27,6 +27,7 @@ func TestGetActivitySummaries(t *testing.T) {
 	expectedFirst := model.ActivitySummary{
 		Id:                 model.ActivityId(202315892),
 		Name:               "Headlands w MC",
+		Type:               "Ride",
 		Athlete:            &model.Athlete{Id: 471686},
 		StartDate:          time.Date(2014, 10, 2, 13, 12, 24, 0, time.UTC),
 		StartDateLocal:     time.Date(2014, 10, 2, 6, 12, 24, 0, time.UTC),
@@ -37,6 +38,9 @@ func TestGetActivitySummaries(t *testing.T) {
 		TotalElevationGain: 796.3,
 		AverageSpeed:       6.807,
 		MaxSpeed:           14.7,
+		KudosCount:         13,
+		CommentCount:       4,
+		Map:                &model.Map{Id: "a202315892", SummaryPolyline: "fake"},
 	}
 	if !reflect.DeepEqual(&expectedFirst, summaries[0]) {
 		t.Fatalf("Summaries were not the same. expected=%s, actual=%s", &expectedFirst, summaries[0])
@@ -45,6 +49,7 @@ func TestGetActivitySummaries(t *testing.T) {
 	expectedSecond := model.ActivitySummary{
 		Id:                 model.ActivityId(203378452),
 		Name:               "Gran Fondo",
+		Type:               "Ride",
 		Athlete:            &model.Athlete{Id: 471686},
 		StartDate:          time.Date(2014, 10, 4, 15, 7, 31, 0, time.UTC),
 		StartDateLocal:     time.Date(2014, 10, 4, 8, 7, 31, 0, time.UTC),
@@ -55,6 +60,8 @@ func TestGetActivitySummaries(t *testing.T) {
 		TotalElevationGain: 2523.8,
 		AverageSpeed:       7.523,
 		MaxSpeed:           16.1,
+		KudosCount:         15,
+		Map:                &model.Map{Id: "a203378452", SummaryPolyline: "fake"},
 	}
 	if !reflect.DeepEqual(&expectedSecond, summaries[1]) {
 		t.Fatalf("Summaries were not the same. expected=%s, actual=%s", &expectedSecond, summaries[1])
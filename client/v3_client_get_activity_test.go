@@ -33,6 +33,8 @@ func TestGetActivity(t *testing.T) {
 	expectedSegmentEffort := model.SegmentEffort{
 		Id:             4792121264,
 		ElapsedTime:    877,
+		StartIndex:     245,
+		EndIndex:       406,
 		StartDate:      time.Date(2014, 10, 4, 15, 38, 36, 0, time.UTC),
 		StartDateLocal: time.Date(2014, 10, 4, 8, 38, 36, 0, time.UTC),
 		PrRank:         1,
@@ -43,6 +45,7 @@ func TestGetActivity(t *testing.T) {
 	expectedActivity := model.Activity{
 		Id:                 203378452,
 		Name:               "Gran Fondo",
+		Type:               "Ride",
 		Athlete:            &model.Athlete{Id: 471686},
 		StartDate:          time.Date(2014, 10, 4, 15, 7, 31, 0, time.UTC),
 		StartDateLocal:     time.Date(2014, 10, 4, 8, 7, 31, 0, time.UTC),
@@ -53,6 +56,8 @@ func TestGetActivity(t *testing.T) {
 		TotalElevationGain: 2523.8,
 		AverageSpeed:       7.523,
 		MaxSpeed:           16.1,
+		KudosCount:         14,
+		Map:                &model.Map{Id: "a203378452", Polyline: "fake", SummaryPolyline: "fake"},
 		SegmentEfforts:     []*model.SegmentEffort{&expectedSegmentEffort},
 	}
 	if !reflect.DeepEqual(&expectedActivity, activity) {
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestGetAthlete(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	rawClient.Gets[noParamsUrl(rawClient, athleteUrl, t)] = expectedBody([]byte(athleteJson))
+
+	athlete, err := client.GetAthlete()
+	if err != nil {
+		t.Fatalf("Unexpected error for GetAthlete. error=%s", err)
+	}
+
+	expected := model.Athlete{Id: 227615, FirstName: "John", LastName: "Applestrava", MeasurementPreference: "feet", Ftp: 280}
+	if !reflect.DeepEqual(&expected, athlete) {
+		t.Fatalf("Athletes were not the same. expected=%v, actual=%v", &expected, athlete)
+	}
+}
+
+// This should really be in a file
+const athleteJson = `
+{
+    "id": 227615,
+    "resource_state": 3,
+    "firstname": "John",
+    "lastname": "Applestrava",
+    "city": "San Francisco",
+    "state": "California",
+    "country": "United States",
+    "sex": "M",
+    "friend": null,
+    "follower": null,
+    "premium": true,
+    "created_at": "2008-01-01T17:44:00Z",
+    "updated_at": "2013-09-04T20:00:50Z",
+    "measurement_preference": "feet",
+    "ftp": 280,
+    "weight": 68.7
+}
+`
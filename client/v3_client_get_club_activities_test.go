@@ -0,0 +1,36 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestGetClubActivities(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	newer := model.ActivitySummary{Id: model.ActivityId(22), Athlete: &model.Athlete{Id: 1}}
+	older := model.ActivitySummary{Id: model.ActivityId(11), Athlete: &model.Athlete{Id: 2}}
+
+	url := clubActivitiesUrl(model.ClubId(1))
+	rawClient.Gets[listPageUrl(rawClient, url, 1, t)] = expectedBody([]byte(fmt.Sprintf("[%s, %s]", toJson(newer, t), toJson(older, t))))
+	rawClient.Gets[listPageUrl(rawClient, url, 2, t)] = expectedBody([]byte("[]"))
+
+	summaries, err := client.GetClubActivities(model.ClubId(1)).All()
+	if err != nil {
+		t.Fatalf("Unexpected error for GetClubActivities. error=%s", err)
+	}
+
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 summaries but got %d", len(summaries))
+	}
+
+	// can't do use DeepEqual since times are unmarshalling in different location
+	if newer.Id != summaries[0].Id || newer.Athlete.Id != summaries[0].Athlete.Id {
+		t.Fatalf("Summaries were not the same. expected=%v, actual=%v", &newer, summaries[0])
+	}
+	if older.Id != summaries[1].Id || older.Athlete.Id != summaries[1].Athlete.Id {
+		t.Fatalf("Summaries were not the same. expected=%v, actual=%v", &older, summaries[1])
+	}
+}
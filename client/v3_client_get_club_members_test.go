@@ -0,0 +1,73 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestGetClubMembers(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	first := model.Athlete{Id: 11, FirstName: "First"}
+	second := model.Athlete{Id: 22, FirstName: "Second"}
+
+	url := clubMembersUrl(model.ClubId(1))
+	rawClient.Gets[listPageUrl(rawClient, url, 1, t)] = expectedBody([]byte(fmt.Sprintf("[%s]", toJson(first, t))))
+	rawClient.Gets[listPageUrl(rawClient, url, 2, t)] = expectedBody([]byte(fmt.Sprintf("[%s]", toJson(second, t))))
+	rawClient.Gets[listPageUrl(rawClient, url, 3, t)] = expectedBody([]byte("[]"))
+
+	members := client.GetClubMembers(model.ClubId(1))
+
+	page, err := members.Next()
+	if err != nil {
+		t.Fatalf("Unexpected error for first page. error=%s", err)
+	}
+	if len(page) != 1 || *page[0] != first {
+		t.Fatalf("First page was not as expected. expected=%v, actual=%v", first, page)
+	}
+
+	remaining, err := members.All()
+	if err != nil {
+		t.Fatalf("Unexpected error for remaining pages. error=%s", err)
+	}
+	if len(remaining) != 1 || *remaining[0] != second {
+		t.Fatalf("Remaining pages were not as expected. expected=%v, actual=%v", second, remaining)
+	}
+
+	// Iteration is complete, so no more requests should be made
+	page, err = members.Next()
+	if err != nil || len(page) != 0 {
+		t.Fatalf("Expected empty page after iteration completed. page=%v, error=%s", page, err)
+	}
+}
+
+func TestGetClubAdmins(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	admin := model.Athlete{Id: 33, FirstName: "Admin"}
+
+	url := clubAdminsUrl(model.ClubId(1))
+	rawClient.Gets[listPageUrl(rawClient, url, 1, t)] = expectedBody([]byte(fmt.Sprintf("[%s]", toJson(admin, t))))
+	rawClient.Gets[listPageUrl(rawClient, url, 2, t)] = expectedBody([]byte("[]"))
+
+	admins, err := client.GetClubAdmins(model.ClubId(1)).All()
+	if err != nil {
+		t.Fatalf("Unexpected error for GetClubAdmins. error=%s", err)
+	}
+	if len(admins) != 1 || *admins[0] != admin {
+		t.Fatalf("Admins were not as expected. expected=%v, actual=%v", admin, admins)
+	}
+}
+
+func TestGetClubMembers_Error(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	url := clubMembersUrl(model.ClubId(1))
+	rawClient.Gets[listPageUrl(rawClient, url, 1, t)] = bodyOrError{Error: fmt.Errorf("boom")}
+
+	if _, err := client.GetClubMembers(model.ClubId(1)).All(); err == nil {
+		t.Fatalf("Expected error from GetClubMembers")
+	}
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestGetClub(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	rawClient.Gets[noParamsUrl(rawClient, clubUrl(model.ClubId(1)), t)] = expectedBody([]byte(clubJson))
+
+	club, err := client.GetClub(model.ClubId(1))
+	if err != nil {
+		t.Fatalf("Unexpected error for GetClub. error=%s", err)
+	}
+
+	if !reflect.DeepEqual(&expectedClub, club) {
+		t.Fatalf("Clubs were not the same. expected=%v, actual=%v", &expectedClub, club)
+	}
+}
+
+func TestGetAthleteClubs(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	rawClient.Gets[noParamsUrl(rawClient, athleteClubsUrl, t)] = expectedBody([]byte("[" + clubJson + "]"))
+
+	clubs, err := client.GetAthleteClubs()
+	if err != nil {
+		t.Fatalf("Unexpected error for GetAthleteClubs. error=%s", err)
+	}
+
+	if len(clubs) != 1 {
+		t.Fatalf("Expected 1 club but got %d", len(clubs))
+	}
+	if !reflect.DeepEqual(&expectedClub, clubs[0]) {
+		t.Fatalf("Clubs were not the same. expected=%v, actual=%v", &expectedClub, clubs[0])
+	}
+}
+
+var expectedClub = model.Club{
+	Id:          1,
+	Name:        "Team Strava Cycling",
+	Description: "From the people who brought you strava.com",
+	ClubType:    "company",
+	SportType:   "cycling",
+	City:        "San Francisco",
+	State:       "California",
+	Country:     "United States",
+	Private:     true,
+	MemberCount: 116,
+}
+
+// This should really be in a file
+const clubJson = `
+{
+    "id": 1,
+    "resource_state": 3,
+    "name": "Team Strava Cycling",
+    "profile_medium": "http://pics.com/clubs/1/medium.jpg",
+    "profile": "http://pics.com/clubs/1/large.jpg",
+    "description": "From the people who brought you strava.com",
+    "club_type": "company",
+    "sport_type": "cycling",
+    "city": "San Francisco",
+    "state": "California",
+    "country": "United States",
+    "private": true,
+    "member_count": 116
+}
+`
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestGetGear(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	rawClient.Gets[noParamsUrl(rawClient, gearUrl(model.GearId("b105763")), t)] = expectedBody([]byte(gearJson))
+
+	gear, err := client.GetGear(model.GearId("b105763"))
+	if err != nil {
+		t.Fatalf("Unexpected error for GetGear. error=%s", err)
+	}
+
+	expected := model.Gear{
+		Id:          "b105763",
+		Name:        "Cannondale TT",
+		BrandName:   "Cannondale",
+		ModelName:   "Slice",
+		Description: "Race day only",
+		Distance:    476612.9,
+		Primary:     false,
+		Retired:     true,
+	}
+	if !reflect.DeepEqual(&expected, gear) {
+		t.Fatalf("Gear was not the same. expected=%v, actual=%v", &expected, gear)
+	}
+}
+
+// This should really be in a file
+const gearJson = `
+{
+    "id": "b105763",
+    "primary": false,
+    "name": "Cannondale TT",
+    "distance": 476612.9,
+    "brand_name": "Cannondale",
+    "model_name": "Slice",
+    "frame_type": 3,
+    "description": "Race day only",
+    "retired": true,
+    "resource_state": 3
+}
+`
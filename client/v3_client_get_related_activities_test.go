@@ -27,6 +27,7 @@ func TestGetRelatedActivitySummaries(t *testing.T) {
 	expectedFirst := model.ActivitySummary{
 		Id:   model.ActivityId(9837863),
 		Name: "Levi's Gran Fondo - Missed 16k because of mechanical issues but got all the climbs in",
+		Type: "Ride",
 		Athlete: &model.Athlete{
 			Id:        699515,
 			FirstName: "Some",
@@ -42,6 +43,10 @@ func TestGetRelatedActivitySummaries(t *testing.T) {
 		TotalElevationGain: 2240.0,
 		AverageSpeed:       7.124,
 		MaxSpeed:           17.8,
+		GearId:             "b616042",
+		KudosCount:         7,
+		CommentCount:       2,
+		Map:                &model.Map{Id: "a203353614", SummaryPolyline: "xyz"},
 	}
 	if !reflect.DeepEqual(&expectedFirst, summaries[0]) {
 		t.Fatalf("Summaries were not the same. expected=%s, actual=%s", &expectedFirst, summaries[0])
@@ -50,6 +55,7 @@ func TestGetRelatedActivitySummaries(t *testing.T) {
 	expectedSecond := model.ActivitySummary{
 		Id:   model.ActivityId(29823897),
 		Name: "Levi's Gran Fondo with heat wave",
+		Type: "Ride",
 		Athlete: &model.Athlete{
 			Id:        11235813,
 			FirstName: "Bea",
@@ -66,6 +72,10 @@ func TestGetRelatedActivitySummaries(t *testing.T) {
 		TotalElevationGain: 2277.0,
 		AverageSpeed:       7.856,
 		MaxSpeed:           16.9,
+		GearId:             "b1083842",
+		KudosCount:         27,
+		CommentCount:       2,
+		Map:                &model.Map{Id: "a203335389", SummaryPolyline: "blah"},
 	}
 	if !reflect.DeepEqual(&expectedSecond, summaries[1]) {
 		t.Fatalf("Summaries were not the same. expected=%s, actual=%s", &expectedSecond, summaries[1])
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestGetRoute(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	rawClient.Gets[noParamsUrl(rawClient, routeUrl(model.RouteId(1263727)), t)] = expectedBody([]byte(routeJson))
+
+	route, err := client.GetRoute(model.RouteId(1263727))
+	if err != nil {
+		t.Fatalf("Unexpected error for GetRoute. error=%s", err)
+	}
+
+	expectedSegment := model.Segment{
+		Id:            646257,
+		Name:          "Gatorade Climb",
+		Distance:      1439.9,
+		ElevationLow:  66.6,
+		ElevationHigh: 168.3,
+		AverageGrade:  7.1,
+		MaximumGrade:  12.9,
+		ClimbCategory: 1,
+	}
+	expected := model.Route{
+		Id:            1263727,
+		Name:          "Highlands Loop",
+		Description:   "Saturday club loop",
+		Athlete:       &model.Athlete{Id: 227615, FirstName: "John", LastName: "Applestrava"},
+		Type:          model.RideRoute,
+		Distance:      34717.9,
+		ElevationGain: 461.6,
+		Private:       false,
+		Starred:       true,
+		Timestamp:     1402436774,
+		Map:           &model.Map{Id: "r1263727", Polyline: "a}feFbkbjVe@x@", SummaryPolyline: "a}feFbkbjV"},
+		Segments:      []*model.Segment{&expectedSegment},
+	}
+	if !reflect.DeepEqual(&expected, route) {
+		t.Fatalf("Routes were not the same. expected=%v, actual=%v", &expected, route)
+	}
+}
+
+// This should really be in a file
+const routeJson = `
+{
+    "athlete": {
+        "id": 227615,
+        "resource_state": 2,
+        "firstname": "John",
+        "lastname": "Applestrava"
+    },
+    "description": "Saturday club loop",
+    "distance": 34717.9,
+    "elevation_gain": 461.6,
+    "id": 1263727,
+    "map": {
+        "id": "r1263727",
+        "polyline": "a}feFbkbjVe@x@",
+        "summary_polyline": "a}feFbkbjV",
+        "resource_state": 3
+    },
+    "name": "Highlands Loop",
+    "private": false,
+    "resource_state": 3,
+    "starred": true,
+    "sub_type": 1,
+    "timestamp": 1402436774,
+    "type": 1,
+    "segments": [
+        {
+            "id": 646257,
+            "resource_state": 2,
+            "name": "Gatorade Climb",
+            "activity_type": "Ride",
+            "distance": 1439.9,
+            "average_grade": 7.1,
+            "maximum_grade": 12.9,
+            "elevation_high": 168.3,
+            "elevation_low": 66.6,
+            "climb_category": 1,
+            "city": "Mill Valley",
+            "state": "CA",
+            "country": "United States",
+            "private": false
+        }
+    ]
+}
+`
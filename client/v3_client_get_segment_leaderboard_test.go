@@ -0,0 +1,120 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestGetSegmentLeaderboard(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	params := map[string]interface{}{"per_page": segmentLeaderboardPageSize, "page": 1}
+	rawClient.Gets[leaderboardUrl(rawClient, params, t)] = expectedBody([]byte(segmentLeaderboardJson))
+
+	leaderboard, err := client.GetSegmentLeaderboard(model.SegmentId(229781), nil, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error for GetSegmentLeaderboard. error=%s", err)
+	}
+
+	expectedEntry := model.SegmentLeaderboardEntry{
+		Rank:           1,
+		AthleteId:      123529,
+		AthleteName:    "Jim Whimpey",
+		AthleteGender:  "M",
+		ActivityId:     46320211,
+		EffortId:       801006623,
+		ElapsedTime:    360,
+		MovingTime:     360,
+		Distance:       2659.89,
+		AverageHr:      190.5,
+		AverageWatts:   460.8,
+		StartDate:      time.Date(2013, 3, 29, 13, 49, 35, 0, time.UTC),
+		StartDateLocal: time.Date(2013, 3, 29, 6, 49, 35, 0, time.UTC),
+	}
+	expected := model.SegmentLeaderboard{
+		EffortCount: 7037,
+		EntryCount:  7037,
+		Entries:     []*model.SegmentLeaderboardEntry{&expectedEntry},
+	}
+	if !reflect.DeepEqual(&expected, leaderboard) {
+		t.Fatalf("Leaderboards were not the same. expected=%v, actual=%v", &expected, leaderboard)
+	}
+}
+
+func TestGetSegmentLeaderboard_Filter(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	params := map[string]interface{}{
+		"per_page":     segmentLeaderboardPageSize,
+		"page":         2,
+		"gender":       "F",
+		"age_group":    "35_44",
+		"weight_class": "55_64",
+		"following":    true,
+		"club_id":      15,
+		"date_range":   "this_year",
+	}
+	rawClient.Gets[leaderboardUrl(rawClient, params, t)] = expectedBody([]byte(`{"entries": []}`))
+
+	filter := LeaderboardFilter{
+		Gender:      Female,
+		AgeGroup:    Age35To44,
+		WeightClass: Weight55To64Kg,
+		Following:   true,
+		ClubId:      model.ClubId(15),
+		DateRange:   ThisYear,
+	}
+	leaderboard, err := client.GetSegmentLeaderboard(model.SegmentId(229781), &filter, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error for GetSegmentLeaderboard. error=%s", err)
+	}
+
+	if len(leaderboard.Entries) != 0 {
+		t.Fatalf("Expected no entries but got %d", len(leaderboard.Entries))
+	}
+}
+
+func TestGetSegmentLeaderboard_InvalidPage(t *testing.T) {
+	client, _ := newTestClient()
+
+	if _, err := client.GetSegmentLeaderboard(model.SegmentId(229781), nil, 0); err == nil {
+		t.Fatalf("Expected error for page 0")
+	}
+}
+
+func leaderboardUrl(rawClient HttpClient, params map[string]interface{}, t *testing.T) string {
+	url, err := rawClient.AbsoluteUrl(segmentLeaderboardUrl(model.SegmentId(229781)), params)
+	if err != nil {
+		t.Fatalf("Error creating test URL. error=%s", err)
+	}
+	return url
+}
+
+// This should really be in a file
+const segmentLeaderboardJson = `
+{
+    "effort_count": 7037,
+    "entry_count": 7037,
+    "entries": [
+        {
+            "athlete_name": "Jim Whimpey",
+            "athlete_id": 123529,
+            "athlete_gender": "M",
+            "average_hr": 190.5,
+            "average_watts": 460.8,
+            "distance": 2659.89,
+            "elapsed_time": 360,
+            "moving_time": 360,
+            "start_date": "2013-03-29T13:49:35Z",
+            "start_date_local": "2013-03-29T06:49:35Z",
+            "activity_id": 46320211,
+            "effort_id": 801006623,
+            "rank": 1,
+            "athlete_profile": "http://pics.com/227615/large.jpg"
+        }
+    ]
+}
+`
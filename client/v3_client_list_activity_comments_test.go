@@ -0,0 +1,94 @@
+package client
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestListActivityComments(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	url := activityCommentsUrl(model.ActivityId(123))
+	rawClient.Gets[listPageUrl(rawClient, url, 1, t)] = expectedBody([]byte("[" + commentJson + "]"))
+	rawClient.Gets[listPageUrl(rawClient, url, 2, t)] = expectedBody([]byte("[]"))
+
+	comments, err := client.ListActivityComments(model.ActivityId(123)).All()
+	if err != nil {
+		t.Fatalf("Unexpected error for ListActivityComments. error=%s", err)
+	}
+
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 comment but got %d", len(comments))
+	}
+	if !reflect.DeepEqual(&expectedComment, comments[0]) {
+		t.Fatalf("Comments were not the same. expected=%v, actual=%v", &expectedComment, comments[0])
+	}
+}
+
+func TestCreateActivityComment(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	url, _ := rawClient.AbsoluteUrl(activityCommentsUrl(model.ActivityId(123)), map[string]interface{}{"text": "Nice ride"})
+	rawClient.Posts[url] = expectedBody([]byte(commentJson))
+
+	comment, err := client.CreateActivityComment(model.ActivityId(123), "Nice ride")
+	if err != nil {
+		t.Fatalf("Unexpected error for CreateActivityComment. error=%s", err)
+	}
+	if !reflect.DeepEqual(&expectedComment, comment) {
+		t.Fatalf("Comments were not the same. expected=%v, actual=%v", &expectedComment, comment)
+	}
+}
+
+func TestCreateActivityComment_Empty(t *testing.T) {
+	client, _ := newTestClient()
+
+	if _, err := client.CreateActivityComment(model.ActivityId(123), ""); err == nil {
+		t.Fatalf("Expected error for empty comment")
+	}
+}
+
+func TestDeleteActivityComment(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	url := noParamsUrl(rawClient, activityCommentUrl(model.ActivityId(123), model.CommentId(2)), t)
+	rawClient.Deletes[url] = expectedBody([]byte(""))
+
+	if err := client.DeleteActivityComment(model.ActivityId(123), model.CommentId(2)); err != nil {
+		t.Fatalf("Unexpected error for DeleteActivityComment. error=%s", err)
+	}
+
+	rawClient.Deletes[url] = bodyOrError{Error: errors.New("Unexpected HTTP response 403 Forbidden")}
+	if err := client.DeleteActivityComment(model.ActivityId(123), model.CommentId(2)); err == nil {
+		t.Fatalf("Expected error for forbidden delete")
+	}
+}
+
+var expectedComment = model.Comment{
+	Id:         2,
+	ActivityId: 123,
+	Text:       "Nice ride",
+	Athlete:    &model.Athlete{Id: 227615, FirstName: "John", LastName: "Applestrava"},
+	CreatedAt:  time.Date(2012, 12, 20, 23, 55, 29, 0, time.UTC),
+}
+
+// This should really be in a file
+const commentJson = `
+{
+    "id": 2,
+    "resource_state": 2,
+    "text": "Nice ride",
+    "athlete": {
+        "id": 227615,
+        "resource_state": 2,
+        "firstname": "John",
+        "lastname": "Applestrava"
+    },
+    "activity_id": 123,
+    "created_at": "2012-12-20T23:55:29Z"
+}
+`
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestListActivityKudoers(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	kudoer := model.Athlete{Id: 227615, FirstName: "John", LastName: "Applestrava"}
+
+	url := activityKudosUrl(model.ActivityId(123))
+	rawClient.Gets[listPageUrl(rawClient, url, 1, t)] = expectedBody([]byte(fmt.Sprintf("[%s]", toJson(kudoer, t))))
+	rawClient.Gets[listPageUrl(rawClient, url, 2, t)] = expectedBody([]byte("[]"))
+
+	kudoers, err := client.ListActivityKudoers(model.ActivityId(123)).All()
+	if err != nil {
+		t.Fatalf("Unexpected error for ListActivityKudoers. error=%s", err)
+	}
+
+	if len(kudoers) != 1 || *kudoers[0] != kudoer {
+		t.Fatalf("Kudoers were not as expected. expected=%v, actual=%v", kudoer, kudoers)
+	}
+}
+
+func TestGiveKudos(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	rawClient.Posts[noParamsUrl(rawClient, activityKudosUrl(model.ActivityId(123)), t)] = expectedBody([]byte(""))
+
+	if err := client.GiveKudos(model.ActivityId(123)); err != nil {
+		t.Fatalf("Unexpected error for GiveKudos. error=%s", err)
+	}
+}
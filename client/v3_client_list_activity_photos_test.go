@@ -0,0 +1,69 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestListActivityPhotos(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	url, _ := rawClient.AbsoluteUrl(activityPhotosUrl(model.ActivityId(123)), map[string]interface{}{"photo_sources": true, "size": 600})
+	rawClient.Gets[url] = expectedBody([]byte(activityPhotosJson))
+
+	photos, err := client.ListActivityPhotos(model.ActivityId(123), 600)
+	if err != nil {
+		t.Fatalf("Unexpected error for ListActivityPhotos. error=%s", err)
+	}
+
+	expected := model.Photo{
+		UniqueId:   "e1e3f4a3-5b0e-4d5c-9ab2-4a4f5e3c2f11",
+		ActivityId: 123,
+		Urls:       map[string]string{"600": "https://photos.example.com/e1e3f4a3-600x450.jpg"},
+		Caption:    "Top of Hawk Hill",
+		Location:   []float64{37.8331, -122.4834},
+		Source:     1,
+		CreatedAt:  time.Date(2014, 10, 4, 16, 20, 11, 0, time.UTC),
+	}
+	if len(photos) != 1 {
+		t.Fatalf("Expected 1 photo but got %d", len(photos))
+	}
+	if !reflect.DeepEqual(&expected, photos[0]) {
+		t.Fatalf("Photos were not the same. expected=%v, actual=%v", &expected, photos[0])
+	}
+}
+
+func TestListActivityPhotos_InvalidSize(t *testing.T) {
+	client, _ := newTestClient()
+
+	if _, err := client.ListActivityPhotos(model.ActivityId(123), 0); err == nil {
+		t.Fatalf("Expected error for size 0")
+	}
+}
+
+// This should really be in a file
+const activityPhotosJson = `[
+    {
+        "unique_id": "e1e3f4a3-5b0e-4d5c-9ab2-4a4f5e3c2f11",
+        "athlete_id": 227615,
+        "activity_id": 123,
+        "activity_name": "Gran Fondo",
+        "resource_state": 2,
+        "caption": "Top of Hawk Hill",
+        "source": 1,
+        "uploaded_at": "2014-10-04T23:02:10Z",
+        "created_at": "2014-10-04T16:20:11Z",
+        "created_at_local": "2014-10-04T09:20:11Z",
+        "urls": {
+            "600": "https://photos.example.com/e1e3f4a3-600x450.jpg"
+        },
+        "sizes": {
+            "600": [600, 450]
+        },
+        "default_photo": false,
+        "location": [37.8331, -122.4834]
+    }
+]`
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestListAthleteRoutes(t *testing.T) {
+	client, rawClient := newTestClient()
+
+	first := model.Route{Id: 1, Name: "First"}
+	second := model.Route{Id: 2, Name: "Second"}
+
+	url := athleteRoutesUrl(model.AthleteId(227615))
+	rawClient.Gets[listPageUrl(rawClient, url, 1, t)] = expectedBody([]byte(fmt.Sprintf("[%s, %s]", toJson(first, t), toJson(second, t))))
+	rawClient.Gets[listPageUrl(rawClient, url, 2, t)] = expectedBody([]byte("[]"))
+
+	routes, err := client.ListAthleteRoutes(model.AthleteId(227615)).All()
+	if err != nil {
+		t.Fatalf("Unexpected error for ListAthleteRoutes. error=%s", err)
+	}
+
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes but got %d", len(routes))
+	}
+	if routes[0].Id != first.Id || routes[1].Id != second.Id {
+		t.Fatalf("Routes were not as expected. expected=%v, actual=%v", []model.RouteId{first.Id, second.Id}, []model.RouteId{routes[0].Id, routes[1].Id})
+	}
+}
@@ -28,3 +28,19 @@ func fullActivityUrl(activityId model.ActivityId, rawClient *testHttpClient, t *
 
 	return url
 }
+
+func listPageUrl(rawClient HttpClient, relativeUrl string, page int, t *testing.T) string {
+	url, err := rawClient.AbsoluteUrl(relativeUrl, map[string]interface{}{"per_page": listPageSize, "page": page})
+	if err != nil {
+		t.Fatalf("Error creating test URL. error=%s", err)
+	}
+	return url
+}
+
+func noParamsUrl(rawClient HttpClient, relativeUrl string, t *testing.T) string {
+	url, err := rawClient.AbsoluteUrl(relativeUrl, make(map[string]interface{}))
+	if err != nil {
+		t.Fatalf("Error creating test URL. error=%s", err)
+	}
+	return url
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func clubMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	clubFlag := flags.Int64("clubId", 0, "club to print activities for; lists the athlete's clubs if not set")
+	membersFlag := flags.Bool("members", false, "print club members instead of activities")
+	cacheFlag := addCacheFlag(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *accessTokenFlag == "" {
+		return usageErrorf("--accessToken is required")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *cacheFlag); err != nil {
+		return err
+	}
+
+	stravaClient, err := newClient(*accessTokenFlag, *cacheFlag)
+	if err != nil {
+		return fmt.Errorf("creating client: %s", err)
+	}
+	clubId := model.ClubId(*clubFlag)
+
+	switch {
+	case clubId == 0:
+		clubs, err := stravaClient.GetAthleteClubs()
+		if err != nil {
+			return fmt.Errorf("getting clubs: %s", err)
+		}
+		return outputFlags.print(clubTable(clubs))
+	case *membersFlag:
+		members, err := stravaClient.GetClubMembers(clubId).All()
+		if err != nil {
+			return fmt.Errorf("getting club members: %s", err)
+		}
+		return outputFlags.print(athleteTable(members))
+	default:
+		summaries, err := stravaClient.GetClubActivities(clubId).All()
+		if err != nil {
+			return fmt.Errorf("getting club activities: %s", err)
+		}
+		return outputFlags.print(clubActivityTable(summaries))
+	}
+}
+
+func clubTable(clubs []*model.Club) *table {
+	t := newTable("id", "name", "sport_type", "city", "member_count")
+	for _, club := range clubs {
+		t.add(club.Id, club.Name, club.SportType, club.City, club.MemberCount)
+	}
+	return t
+}
+
+func athleteTable(athletes []*model.Athlete) *table {
+	t := newTable("id", "first_name", "last_name")
+	for _, athlete := range athletes {
+		t.add(athlete.Id, athlete.FirstName, athlete.LastName)
+	}
+	return t
+}
+
+// Summary table prefixed by the athlete who did the activity.
+func clubActivityTable(summaries []*model.ActivitySummary) *table {
+	t := summaryTable(summaries)
+	t.columns = append([]string{"athlete_id", "athlete_first_name", "athlete_last_name"}, t.columns...)
+	for i, summary := range summaries {
+		athlete := summary.Athlete
+		if athlete == nil {
+			athlete = &model.Athlete{}
+		}
+		t.rows[i] = append([]interface{}{athlete.Id, athlete.FirstName, athlete.LastName}, t.rows[i]...)
+	}
+	return t
+}
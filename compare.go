@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/alecholmes/strava/analysis"
+	"github.com/alecholmes/strava/model"
+)
+
+// Compares the segment efforts of two activities, or with --streams where along the way
+// time was gained or lost.
+func compareMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	streamsFlag := flags.Bool("streams", false, "compare time at each interval of distance rather than segment efforts")
+	intervalFlag := flags.Float64("interval", 1000, "meters between distances compared with --streams")
+	storeFlags := addStoreFlags(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		return usageErrorf("two activity ids are required")
+	}
+	activityIds := make([]model.ActivityId, 2)
+	for i, arg := range flags.Args() {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return usageErrorf("invalid activity id %s", arg)
+		}
+		activityIds[i] = model.ActivityId(id)
+	}
+	if *intervalFlag <= 0 {
+		return usageErrorf("--interval must be positive")
+	}
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *storeFlags.cacheDir); err != nil {
+		return err
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	if *streamsFlag {
+		a, err := source.GetActivityStreams(activityIds[0])
+		if err != nil {
+			return fmt.Errorf("getting streams of activity %d: %s", activityIds[0], err)
+		}
+		b, err := source.GetActivityStreams(activityIds[1])
+		if err != nil {
+			return fmt.Errorf("getting streams of activity %d: %s", activityIds[1], err)
+		}
+		return outputFlags.print(distanceComparisonTable(analysis.CompareStreams(a, b, *intervalFlag)))
+	}
+
+	activities, err := source.GetActivities(activityIds)
+	if err != nil {
+		return fmt.Errorf("getting activities: %s", err)
+	}
+	if len(activities) != 2 {
+		return fmt.Errorf("activities %d and %d were not both found", activityIds[0], activityIds[1])
+	}
+
+	return outputFlags.print(effortComparisonTable(analysis.CompareEfforts(activities[0], activities[1])))
+}
+
+// Deltas are signed seconds, negative where the second activity was faster. The last row totals
+// the segments both activities have.
+func effortComparisonTable(comparisons []*analysis.EffortComparison) *table {
+	t := newTable("segment_id", "segment_name", "time_a", "time_b", "delta", "change_percent")
+
+	var totalA, totalB uint64
+	for _, c := range comparisons {
+		t.add(c.Segment.Id, c.Segment.Name, duration(c.A.ElapsedTime), duration(c.B.ElapsedTime), c.Delta(), c.Change())
+		totalA += uint64(c.A.ElapsedTime)
+		totalB += uint64(c.B.ElapsedTime)
+	}
+
+	change := 0.0
+	if totalA > 0 {
+		change = (float64(totalB) - float64(totalA)) / float64(totalA) * 100
+	}
+	t.add("", "Total", duration(totalA), duration(totalB), int64(totalB)-int64(totalA), change)
+	return t
+}
+
+func distanceComparisonTable(comparisons []*analysis.DistanceComparison) *table {
+	t := newTable("distance", "time_a", "time_b", "delta", "split_delta")
+	for _, c := range comparisons {
+		t.add(distance(c.Distance), duration(c.TimeA+0.5), duration(c.TimeB+0.5), c.Delta, c.SplitDelta)
+	}
+	return t
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// The profile used when none is named
+const DefaultProfile = "default"
+
+const (
+	appDir          = "strava"
+	configFile      = "config.json"
+	credentialsFile = "credentials.json"
+)
+
+// Defaults for CLI flags, so they don't need to be given on every command.
+// Empty fields leave the flag's own default.
+type Profile struct {
+	Format    string `json:"format"`
+	Delimiter string `json:"delimiter"`
+	Units     string `json:"units"`
+	Store     string `json:"store"` // Directory of the local activity store
+	Cache     string `json:"cache"` // Directory to cache Strava responses in
+}
+
+// The config file, holding named profiles.
+type Config struct {
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// The named profile, or an empty profile if it isn't configured.
+func (c *Config) Profile(name string) *Profile {
+	if profile, found := c.Profiles[name]; found && profile != nil {
+		return profile
+	}
+	return &Profile{}
+}
+
+// The directory holding the config and credentials: $XDG_CONFIG_HOME/strava,
+// or ~/.config/strava if XDG_CONFIG_HOME is not set.
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, appDir), nil
+	}
+
+	home := os.Getenv("HOME")
+	if home == "" {
+		return "", errors.New("neither XDG_CONFIG_HOME nor HOME is set")
+	}
+	return filepath.Join(home, ".config", appDir), nil
+}
+
+// Load the config file in dir. A missing file is an empty config.
+func Load(dir string) (*Config, error) {
+	config := &Config{Profiles: make(map[string]*Profile)}
+	if err := readJson(filepath.Join(dir, configFile), config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Read a JSON file into obj, leaving obj unchanged if the file doesn't exist.
+func readJson(path string, obj interface{}) error {
+	body, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, obj)
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/alecholmes/strava/auth"
+)
+
+func TestDir(t *testing.T) {
+	defer os.Setenv("XDG_CONFIG_HOME", os.Getenv("XDG_CONFIG_HOME"))
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+
+	os.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if dir, err := Dir(); err != nil || dir != "/xdg/strava" {
+		t.Fatalf("Dir was not as expected. expected=/xdg/strava, actual=%s, error=%v", dir, err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", "")
+	os.Setenv("HOME", "/home/athlete")
+	if dir, err := Dir(); err != nil || dir != "/home/athlete/.config/strava" {
+		t.Fatalf("Dir was not as expected. expected=/home/athlete/.config/strava, actual=%s, error=%v", dir, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, cleanup := newTestDir(t)
+	defer cleanup()
+
+	config, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error for Load. error=%s", err)
+	}
+	if actual := config.Profile(DefaultProfile); !reflect.DeepEqual(actual, &Profile{}) {
+		t.Fatalf("Missing profile was not as expected. expected=%v, actual=%v", &Profile{}, actual)
+	}
+
+	body := `{"profiles": {"default": {"format": "table", "units": "auto"}, "club": {"delimiter": "\t", "store": "/tmp/club"}}}`
+	if err := ioutil.WriteFile(filepath.Join(dir, configFile), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error for Load. error=%s", err)
+	}
+
+	expected := &Profile{Format: "table", Units: "auto"}
+	if actual := config.Profile(DefaultProfile); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Default profile was not as expected. expected=%v, actual=%v", expected, actual)
+	}
+	expected = &Profile{Delimiter: "\t", Store: "/tmp/club"}
+	if actual := config.Profile("club"); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Club profile was not as expected. expected=%v, actual=%v", expected, actual)
+	}
+}
+
+func TestCredentialStore(t *testing.T) {
+	dir, cleanup := newTestDir(t)
+	defer cleanup()
+	dir = filepath.Join(dir, "strava")
+
+	store, err := OpenCredentials(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error for OpenCredentials. error=%s", err)
+	}
+	if credentials := store.Get(DefaultProfile); credentials != nil {
+		t.Fatalf("Expected no credentials but got %v", credentials)
+	}
+
+	expected := &Credentials{
+		ClientId:     "123",
+		ClientSecret: "secret",
+		Token:        &auth.Token{TokenType: "Bearer", AccessToken: "access", RefreshToken: "refresh", ExpiresAt: 1568775134},
+	}
+	if err := store.Put("club", expected); err != nil {
+		t.Fatalf("Unexpected error for Put. error=%s", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, credentialsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("Credentials file mode was not as expected. expected=%v, actual=%v", os.FileMode(0600), info.Mode().Perm())
+	}
+
+	store, err = OpenCredentials(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error for OpenCredentials. error=%s", err)
+	}
+	if actual := store.Get("club"); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Credentials were not as expected. expected=%v, actual=%v", expected, actual)
+	}
+
+	if err := os.Chmod(filepath.Join(dir, credentialsFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenCredentials(dir); err == nil {
+		t.Fatalf("Expected an error opening readable credentials")
+	}
+}
+
+func newTestDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "config-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/alecholmes/strava/auth"
+)
+
+// OAuth credentials of a profile. The client id and secret are kept so the token can be refreshed.
+type Credentials struct {
+	ClientId     string      `json:"client_id"`
+	ClientSecret string      `json:"client_secret"`
+	Token        *auth.Token `json:"token"`
+}
+
+// Credentials of each profile, kept in a file only readable by its owner.
+type CredentialStore struct {
+	path     string
+	profiles map[string]*Credentials
+}
+
+// Open the credential store in dir. The file is created on the first Put.
+// Fails if the file can be read by anyone but its owner, since the tokens may have leaked.
+func OpenCredentials(dir string) (*CredentialStore, error) {
+	path := filepath.Join(dir, credentialsFile)
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("%s must only be accessible by its owner; run chmod 600 %s", path, path)
+	}
+
+	profiles := make(map[string]*Credentials)
+	if err := readJson(path, &profiles); err != nil {
+		return nil, err
+	}
+	return &CredentialStore{path: path, profiles: profiles}, nil
+}
+
+// The credentials of a profile, or nil if there are none.
+func (s *CredentialStore) Get(profile string) *Credentials {
+	return s.profiles[profile]
+}
+
+// Set the credentials of a profile and write the store.
+func (s *CredentialStore) Put(profile string, credentials *Credentials) error {
+	s.profiles[profile] = credentials
+	return s.write()
+}
+
+// Write the store by writing a temporary file and renaming it over the existing file.
+// Both are created with mode 0600, in a directory with mode 0700.
+func (s *CredentialStore) write() error {
+	body, err := json.MarshalIndent(s.profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	// TempFile creates files with mode 0600
+	tempFile, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(body); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), s.path)
+}
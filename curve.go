@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/alecholmes/strava/analysis"
+	"github.com/alecholmes/strava/client"
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/store"
+	"github.com/alecholmes/strava/units"
+)
+
+// Streams a curve can be computed from, for --stream
+const (
+	powerStream = "power"
+	paceStream  = "pace"
+)
+
+// Prints mean-maximal power or pace over standard durations, optionally drawing it as an SVG chart.
+func curveReportMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	streamFlag := flags.String("stream", powerStream, "stream to find best efforts in: power or pace")
+	fromFlag := flags.String("from", "", "first activity date to include, as YYYY-MM-DD")
+	toFlag := flags.String("to", "", "last activity date to include, as YYYY-MM-DD")
+	perActivityFlag := flags.Bool("perActivity", false, "print the curve of each activity rather than the best across all of them")
+	svgFlag := flags.String("svg", "", "also draw the best curve as an SVG chart in this file")
+	storeFlags := addStoreFlags(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *streamFlag != powerStream && *streamFlag != paceStream {
+		return usageErrorf("--stream must be power or pace")
+	}
+	from, to, err := parseDateRange(*fromFlag, *toFlag)
+	if err != nil {
+		return &usageError{message: err.Error()}
+	}
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *storeFlags.cacheDir); err != nil {
+		return err
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	summaries, err := source.GetActivitySummaries(client.Beginning)
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	curves, err := activityCurves(source, summaries, *streamFlag, from, to)
+	if err != nil {
+		return err
+	}
+	best := analysis.BestCurve(curves, analysis.StandardDurations)
+
+	if *svgFlag != "" {
+		if err := writeCurveSvgFile(*svgFlag, best, *streamFlag, outputFlags.system); err != nil {
+			return fmt.Errorf("writing chart: %s", err)
+		}
+	}
+
+	if *perActivityFlag {
+		all := make([]*analysis.Best, 0)
+		for _, c := range curves {
+			all = append(all, c...)
+		}
+		return outputFlags.print(curveTable(all, summaries, *streamFlag))
+	}
+	return outputFlags.print(curveTable(best, summaries, *streamFlag))
+}
+
+// The curve of each activity started within [from, to) that has the stream.
+func activityCurves(source store.Source, summaries []*model.ActivitySummary, stream string, from time.Time, to time.Time) ([][]*analysis.Best, error) {
+	curves := make([][]*analysis.Best, 0)
+	for _, summary := range summaries {
+		if summary.StartDateLocal.Before(from) || !summary.StartDateLocal.Before(to) {
+			continue
+		}
+
+		streams, err := source.GetActivityStreams(summary.Id)
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("getting streams of activity %d: %s", summary.Id, err)
+		}
+
+		var c []*analysis.Best
+		if stream == powerStream {
+			c = analysis.PowerCurve(summary, streams, analysis.StandardDurations)
+		} else if units.UsesPace(summary.Type) {
+			c = analysis.PaceCurve(summary, streams, analysis.StandardDurations)
+		}
+		if len(c) > 0 {
+			curves = append(curves, c)
+		}
+	}
+	return curves, nil
+}
+
+func curveTable(bests []*analysis.Best, summaries []*model.ActivitySummary, stream string) *table {
+	names := make(map[model.ActivityId]string)
+	for _, summary := range summaries {
+		names[summary.Id] = summary.Name
+	}
+
+	t := newTable("duration", stream, "activity_id", "activity_name", "date")
+	for _, best := range bests {
+		t.add(duration(best.Duration), curveValue(best.Value, stream), best.ActivityId, names[best.ActivityId],
+			best.Date.Format(dateLayout))
+	}
+	return t
+}
+
+// Power in watts, or pace so it is shown per kilometer or mile.
+func curveValue(value float64, stream string) interface{} {
+	if stream == paceStream {
+		return pace(value)
+	}
+	return value
+}
+
+func writeCurveSvgFile(path string, bests []*analysis.Best, stream string, system units.System) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	if err := writeCurveSvg(w, bests, stream, system); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Chart dimensions, in pixels
+const (
+	chartWidth  = 800
+	chartHeight = 400
+	chartMargin = 60
+)
+
+// Draw a curve as a line chart, with durations on a log scale since best efforts change
+// quickly over short durations and slowly over long ones.
+func writeCurveSvg(w io.Writer, bests []*analysis.Best, stream string, system units.System) error {
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n",
+		chartWidth, chartHeight)
+	fmt.Fprintf(w, `<rect width="%d" height="%d" fill="white"/>`+"\n", chartWidth, chartHeight)
+
+	left, right := float64(chartMargin), float64(chartWidth-chartMargin/2)
+	top, bottom := float64(chartMargin/2), float64(chartHeight-chartMargin)
+	fmt.Fprintf(w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n", left, bottom, right, bottom)
+	fmt.Fprintf(w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n", left, top, left, bottom)
+	fmt.Fprintf(w, `<text x="%.1f" y="%.1f" text-anchor="middle">duration</text>`+"\n", (left+right)/2, float64(chartHeight-15))
+	fmt.Fprintf(w, `<text x="15" y="%.1f" text-anchor="middle" transform="rotate(-90 15 %.1f)">%s</text>`+"\n",
+		(top+bottom)/2, (top+bottom)/2, stream)
+
+	durations := analysis.StandardDurations
+	minLog := math.Log(float64(durations[0]))
+	maxLog := math.Log(float64(durations[len(durations)-1]))
+	x := func(seconds uint32) float64 {
+		return left + (math.Log(float64(seconds))-minLog)/(maxLog-minLog)*(right-left)
+	}
+	for _, seconds := range durations {
+		fmt.Fprintf(w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n", x(seconds), top, x(seconds), bottom)
+		fmt.Fprintf(w, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", x(seconds), bottom+18, durationLabel(seconds))
+	}
+
+	maxValue := 0.0
+	for _, best := range bests {
+		maxValue = math.Max(maxValue, best.Value)
+	}
+	y := func(value float64) float64 {
+		if maxValue == 0 {
+			return bottom
+		}
+		return bottom - value/(maxValue*1.1)*(bottom-top)
+	}
+
+	if len(bests) > 0 {
+		fmt.Fprintf(w, `<polyline fill="none" stroke="#fc4c02" stroke-width="2" points="`)
+		for _, best := range bests {
+			fmt.Fprintf(w, "%.1f,%.1f ", x(best.Duration), y(best.Value))
+		}
+		fmt.Fprintf(w, "\"/>\n")
+	}
+	for _, best := range bests {
+		fmt.Fprintf(w, `<circle cx="%.1f" cy="%.1f" r="4" fill="#fc4c02"/>`+"\n", x(best.Duration), y(best.Value))
+		fmt.Fprintf(w, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n",
+			x(best.Duration), y(best.Value)-10, curveLabel(best.Value, stream, system))
+	}
+
+	_, err := fmt.Fprintf(w, "</svg>\n")
+	return err
+}
+
+// Durations as 5s, 1m, and so on.
+func durationLabel(seconds uint32) string {
+	if seconds%60 == 0 {
+		return fmt.Sprintf("%dm", seconds/60)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+func curveLabel(value float64, stream string, system units.System) string {
+	if stream == powerStream {
+		return fmt.Sprintf("%.0f W", value)
+	}
+	if system == "" {
+		return fmt.Sprintf("%.2f m/s", value)
+	}
+	return system.FormatPace(value)
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/alecholmes/strava/geo"
+	"github.com/alecholmes/strava/gpx"
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/polyline"
+	"github.com/alecholmes/strava/store"
+)
+
+// Writes the GPS track of each activity to dir/<activity id>.gpx, or all of them to stdout as
+// a single GeoJSON or KML document. Activities without a track, such as treadmill runs, are skipped.
+func exportMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	afterFlag := flags.Int("afterId", 0, "beginning activity id, exclusive")
+	dirFlag := flags.String("dir", "", "directory to write files into; required for gpx")
+	formatFlag := flags.String("exportFormat", "gpx", "export file format: gpx, geojson or kml")
+	segmentsFlag := flags.Bool("segments", false, "export the segments ridden or run instead of activities; geojson or kml only")
+	simplifyFlag := flags.Float64("simplify", 0, "simplify tracks to within this many meters; geojson or kml only")
+	detailedFlag := flags.Bool("detailed", false, "export full resolution activity tracks from location streams rather than summary polylines; geojson or kml only")
+	storeFlags := addStoreFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+	switch *formatFlag {
+	case "gpx":
+		if *dirFlag == "" {
+			return usageErrorf("--dir is required")
+		}
+		if *segmentsFlag || *simplifyFlag != 0 || *detailedFlag {
+			return usageErrorf("--segments, --simplify and --detailed require --exportFormat geojson or kml")
+		}
+	case "geojson", "kml":
+	default:
+		return usageErrorf("--exportFormat must be gpx, geojson or kml")
+	}
+	if *simplifyFlag < 0 {
+		return usageErrorf("--simplify must not be negative")
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	summaries, err := source.GetActivitySummaries(model.ActivityId(*afterFlag))
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	if *formatFlag == "gpx" {
+		return exportGpxFiles(source, summaries, *dirFlag)
+	}
+
+	var features []*geo.Feature
+	if *segmentsFlag {
+		features, err = segmentFeatures(source, summaries)
+	} else {
+		features, err = activityFeatures(source, summaries, *detailedFlag || *storeFlags.offline, *detailedFlag)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, feature := range features {
+		feature.Track = geo.Simplify(feature.Track, *simplifyFlag)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	if *formatFlag == "kml" {
+		name := "Activities"
+		if *segmentsFlag {
+			name = "Segments"
+		}
+		err = geo.WriteKml(w, name, features)
+	} else {
+		err = geo.WriteGeoJson(w, features)
+	}
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func exportGpxFiles(source store.Source, summaries []*model.ActivitySummary, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, summary := range summaries {
+		streams, err := source.GetActivityStreams(summary.Id)
+		if err == store.ErrNotFound || (err == nil && len(streams.LatLng) == 0) {
+			fmt.Fprintf(os.Stderr, "Skipped activity %d: no track\n", summary.Id)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("getting streams of activity %d: %s", summary.Id, err)
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("%d.gpx", summary.Id))
+		if err := exportGpx(path, summary, streams); err != nil {
+			return fmt.Errorf("exporting activity %d: %s", summary.Id, err)
+		}
+		fmt.Println(path)
+	}
+
+	return nil
+}
+
+func exportGpx(path string, summary *model.ActivitySummary, streams *model.Streams) error {
+	file := &gpx.File{
+		Activity: &model.Activity{Id: summary.Id, Name: summary.Name, Type: summary.Type, StartDate: summary.StartDate},
+		Streams:  streams,
+	}
+
+	var buf bytes.Buffer
+	if err := gpx.Encode(&buf, file); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Activities as features, tracked as by activityTrack. Activities without a track, or with an
+// invalid polyline, are skipped.
+func activityFeatures(source store.Source, summaries []*model.ActivitySummary, useStreams bool, detailed bool) ([]*geo.Feature, error) {
+	features := make([]*geo.Feature, 0, len(summaries))
+	for _, summary := range summaries {
+		track, err := activityTrack(source, summary, useStreams, detailed)
+		if err == polyline.ErrInvalid {
+			fmt.Fprintf(os.Stderr, "Skipped activity %d: invalid polyline\n", summary.Id)
+			continue
+		} else if err != nil {
+			return nil, err
+		} else if len(track) == 0 {
+			fmt.Fprintf(os.Stderr, "Skipped activity %d: no track\n", summary.Id)
+			continue
+		}
+		features = append(features, geo.ActivityFeature(summary, track))
+	}
+	return features, nil
+}
+
+// Segments of activities as features, each tracked by the part of the location stream of the
+// first activity with an effort on it. Segments are included once, in the order first ridden or run.
+func segmentFeatures(source store.Source, summaries []*model.ActivitySummary) ([]*geo.Feature, error) {
+	activityIds := make([]model.ActivityId, len(summaries))
+	for i, summary := range summaries {
+		activityIds[i] = summary.Id
+	}
+
+	activities, err := source.GetActivities(activityIds)
+	if err != nil {
+		return nil, fmt.Errorf("getting activities: %s", err)
+	}
+
+	features := make([]*geo.Feature, 0)
+	exported := make(map[model.SegmentId]bool)
+	for _, activity := range activities {
+		var latLng [][2]float64
+		for _, effort := range activity.SegmentEfforts {
+			if effort.Segment == nil || exported[effort.Segment.Id] {
+				continue
+			}
+
+			// Streams are only fetched once an activity has a segment still to export
+			if latLng == nil {
+				streams, err := source.GetActivityStreams(activity.Id)
+				if err == store.ErrNotFound {
+					break
+				} else if err != nil {
+					return nil, fmt.Errorf("getting streams of activity %d: %s", activity.Id, err)
+				}
+				if latLng = streams.LatLng; len(latLng) == 0 {
+					break
+				}
+			}
+
+			if effort.StartIndex >= effort.EndIndex || int(effort.EndIndex) >= len(latLng) {
+				continue
+			}
+			exported[effort.Segment.Id] = true
+			features = append(features, geo.SegmentFeature(effort.Segment, latLng[effort.StartIndex:effort.EndIndex+1]))
+		}
+	}
+	return features, nil
+}
@@ -0,0 +1,376 @@
+package fit
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// FIT timestamps are seconds since this time
+var fitEpoch = time.Date(1989, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// Positions are in semicircles, where 2^31 semicircles are 180 degrees
+const semicirclesToDegrees = 180 / float64(1<<31)
+
+// Global message numbers
+const (
+	sessionMessage          = 18
+	lapMessage              = 19
+	recordMessage           = 20
+	activityMessage         = 34
+	fieldDescriptionMessage = 206
+)
+
+// Common to all messages
+const timestampField = 253
+
+const (
+	recordPositionLat      = 0
+	recordPositionLong     = 1
+	recordAltitude         = 2 // Meters, scale 5, offset 500
+	recordHeartRate        = 3
+	recordCadence          = 4
+	recordDistance         = 5 // Meters, scale 100
+	recordSpeed            = 6 // Meters/sec, scale 1000
+	recordPower            = 7
+	recordTemperature      = 13
+	recordEnhancedSpeed    = 73
+	recordEnhancedAltitude = 78
+)
+
+// Lap and session messages have the same fields with different numbers.
+type summaryFields struct {
+	StartTime        byte
+	TotalElapsedTime byte // Seconds, scale 1000
+	TotalTimerTime   byte // Seconds, scale 1000
+	TotalDistance    byte // Meters, scale 100
+	AvgSpeed         byte // Meters/sec, scale 1000
+	MaxSpeed         byte // Meters/sec, scale 1000
+	AvgHeartRate     byte
+	MaxHeartRate     byte
+	AvgCadence       byte
+	AvgPower         byte
+	TotalAscent      byte // Meters
+	EnhancedAvgSpeed byte
+	EnhancedMaxSpeed byte
+	Sport            byte // Sessions only
+	SubSport         byte // Sessions only
+}
+
+var lapFields = &summaryFields{
+	StartTime: 2, TotalElapsedTime: 7, TotalTimerTime: 8, TotalDistance: 9, AvgSpeed: 13, MaxSpeed: 14,
+	AvgHeartRate: 15, MaxHeartRate: 16, AvgCadence: 17, AvgPower: 19, TotalAscent: 21,
+	EnhancedAvgSpeed: 110, EnhancedMaxSpeed: 111,
+}
+
+var sessionFields = &summaryFields{
+	StartTime: 2, TotalElapsedTime: 7, TotalTimerTime: 8, TotalDistance: 9, AvgSpeed: 14, MaxSpeed: 15,
+	AvgHeartRate: 16, MaxHeartRate: 17, AvgCadence: 18, AvgPower: 20, TotalAscent: 22,
+	EnhancedAvgSpeed: 124, EnhancedMaxSpeed: 125,
+	Sport: 5, SubSport: 6,
+}
+
+const activityLocalTimestamp = 5
+
+const (
+	fieldDescriptionDeveloperIndex = 0
+	fieldDescriptionNumber         = 1
+	fieldDescriptionBaseType       = 2
+	fieldDescriptionName           = 3
+	fieldDescriptionUnits          = 8
+)
+
+// Strava activity types by FIT sport
+var sportTypes = map[int]string{
+	1:  "Run",
+	2:  "Ride",
+	4:  "Workout",
+	5:  "Swim",
+	10: "Workout",
+	11: "Walk",
+	12: "NordicSki",
+	13: "AlpineSki",
+	14: "Snowboard",
+	15: "Rowing",
+	17: "Hike",
+	19: "StandUpPaddling",
+	37: "StandUpPaddling",
+	41: "Kayaking",
+}
+
+// FIT sub sport of activities done in virtual worlds, such as Zwift
+const virtualSubSport = 58
+
+// Developer fields that are used for streams the device didn't record, by lowercase name
+var developerStreams = map[string]func(*model.Streams) *[]float32{
+	"power":   func(s *model.Streams) *[]float32 { return &s.Watts },
+	"cadence": func(s *model.Streams) *[]float32 { return &s.Cadence },
+}
+
+// A decoded FIT activity file.
+type File struct {
+	// Id is 0 and there are no segment efforts, since the activity isn't from Strava
+	Activity *model.Activity
+	Streams  *model.Streams
+
+	// Developer field values of record messages, keyed by field name, such as power from a
+	// running power meter. Each has a value per stream sample, with 0 where it wasn't recorded.
+	DeveloperStreams map[string][]float32
+}
+
+// Decode a FIT activity file, as recorded by a device.
+func Decode(r io.Reader) (*File, error) {
+	messages, developerFields, err := decodeMessages(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*message, 0)
+	laps := make([]*message, 0)
+	sessions := make([]*message, 0)
+	var localOffset time.Duration
+	for _, msg := range messages {
+		switch msg.Global {
+		case recordMessage:
+			if _, found := msg.field(timestampField); found {
+				records = append(records, msg)
+			}
+		case lapMessage:
+			laps = append(laps, msg)
+		case sessionMessage:
+			sessions = append(sessions, msg)
+		case activityMessage:
+			timestamp, found := msg.field(timestampField)
+			localTimestamp, localFound := msg.field(activityLocalTimestamp)
+			if found && localFound {
+				localOffset = time.Duration(localTimestamp-timestamp) * time.Second
+			}
+		}
+	}
+
+	file := &File{Activity: toActivity(sessions, records, localOffset)}
+	file.Streams, file.DeveloperStreams = toStreams(records, developerFields)
+	for i, lap := range laps {
+		file.Activity.Laps = append(file.Activity.Laps, toLap(i+1, lap, records, localOffset))
+	}
+
+	return file, nil
+}
+
+func toTime(timestamp float64) time.Time {
+	return fitEpoch.Add(time.Duration(timestamp) * time.Second)
+}
+
+func toStreams(records []*message, developerFields map[developerKey]*developerField) (*model.Streams, map[string][]float32) {
+	streams := &model.Streams{}
+	developer := make(map[string][]float32)
+	if len(records) == 0 {
+		return streams, developer
+	}
+
+	has := make(map[byte]bool)
+	for _, record := range records {
+		for number := range record.Fields {
+			has[number] = true
+		}
+		for key := range record.Developer {
+			if field := developerFields[key]; field != nil && field.Name != "" {
+				developer[field.Name] = make([]float32, 0, len(records))
+			}
+		}
+	}
+	hasLatLng := has[recordPositionLat] && has[recordPositionLong]
+	altitudeField := byte(recordAltitude)
+	if has[recordEnhancedAltitude] {
+		altitudeField = recordEnhancedAltitude
+	}
+	speedField := byte(recordSpeed)
+	if has[recordEnhancedSpeed] {
+		speedField = recordEnhancedSpeed
+	}
+
+	start, _ := records[0].field(timestampField)
+	var latLng [2]float64
+	var distance, altitude float32
+	for _, record := range records {
+		timestamp, _ := record.field(timestampField)
+		streams.Time = append(streams.Time, uint32(timestamp-start))
+
+		// Position, distance and altitude carry forward over samples that don't have them
+		if hasLatLng {
+			lat, latFound := record.field(recordPositionLat)
+			lng, lngFound := record.field(recordPositionLong)
+			if latFound && lngFound {
+				latLng = [2]float64{lat * semicirclesToDegrees, lng * semicirclesToDegrees}
+			}
+			streams.LatLng = append(streams.LatLng, latLng)
+		}
+		if has[recordDistance] {
+			if value, found := record.field(recordDistance); found {
+				distance = float32(value / 100)
+			}
+			streams.Distance = append(streams.Distance, distance)
+		}
+		if has[altitudeField] {
+			if value, found := record.field(altitudeField); found {
+				altitude = float32(value/5 - 500)
+			}
+			streams.Altitude = append(streams.Altitude, altitude)
+		}
+
+		if has[speedField] {
+			streams.VelocitySmooth = append(streams.VelocitySmooth, float32(record.Fields[speedField]/1000))
+		}
+		if has[recordHeartRate] {
+			streams.Heartrate = append(streams.Heartrate, float32(record.Fields[recordHeartRate]))
+		}
+		if has[recordCadence] {
+			streams.Cadence = append(streams.Cadence, float32(record.Fields[recordCadence]))
+		}
+		if has[recordPower] {
+			streams.Watts = append(streams.Watts, float32(record.Fields[recordPower]))
+		}
+		if has[recordTemperature] {
+			streams.Temp = append(streams.Temp, float32(record.Fields[recordTemperature]))
+		}
+
+		values := make(map[string]float32)
+		for key, value := range record.Developer {
+			if field := developerFields[key]; field != nil {
+				values[field.Name] = float32(value)
+			}
+		}
+		for name := range developer {
+			developer[name] = append(developer[name], values[name])
+		}
+	}
+
+	for name, values := range developer {
+		if stream, found := developerStreams[strings.ToLower(name)]; found && len(*stream(streams)) == 0 {
+			*stream(streams) = values
+		}
+	}
+
+	return streams, developer
+}
+
+// Summarize the activity from its sessions, of which multisport activities have several.
+// Files without sessions are summarized from their records.
+func toActivity(sessions []*message, records []*message, localOffset time.Duration) *model.Activity {
+	activity := &model.Activity{}
+
+	for i, session := range sessions {
+		summary := toLap(0, session, records, localOffset)
+		if i == 0 {
+			activity.StartDate = summary.StartDate
+			activity.StartDateLocal = summary.StartDateLocal
+			activity.Type = sessionType(session)
+		}
+		activity.ElapsedTime += summary.ElapsedTime
+		activity.MovingTime += summary.MovingTime
+		activity.Distance += summary.Distance
+		activity.TotalElevationGain += summary.TotalElevationGain
+		activity.MaxSpeed = float32(math.Max(float64(activity.MaxSpeed), float64(summary.MaxSpeed)))
+	}
+
+	if len(sessions) == 0 && len(records) > 0 {
+		start, _ := records[0].field(timestampField)
+		end, _ := records[len(records)-1].field(timestampField)
+		activity.StartDate = toTime(start)
+		activity.StartDateLocal = activity.StartDate.Add(localOffset)
+		activity.ElapsedTime = uint32(end - start)
+		activity.MovingTime = activity.ElapsedTime
+		for i := len(records) - 1; i >= 0; i-- {
+			if distance, found := records[i].field(recordDistance); found {
+				activity.Distance = float32(distance / 100)
+				break
+			}
+		}
+	}
+
+	if activity.MovingTime > 0 {
+		activity.AverageSpeed = activity.Distance / float32(activity.MovingTime)
+	}
+
+	return activity
+}
+
+func sessionType(session *message) string {
+	sport, _ := session.field(sessionFields.Sport)
+	activityType, found := sportTypes[int(sport)]
+	if !found {
+		return "Workout"
+	}
+
+	if subSport, _ := session.field(sessionFields.SubSport); subSport == virtualSubSport && (activityType == "Ride" || activityType == "Run") {
+		return "Virtual" + activityType
+	}
+	return activityType
+}
+
+// Convert a lap or session message to a lap. Its stream indexes are those of the records
+// between its start time and its timestamp, which is when it ended.
+func toLap(index int, msg *message, records []*message, localOffset time.Duration) *model.Lap {
+	fields := lapFields
+	if msg.Global == sessionMessage {
+		fields = sessionFields
+	}
+
+	value := func(field byte, scale float64) float32 {
+		v, _ := msg.field(field)
+		return float32(v / scale)
+	}
+
+	lap := &model.Lap{
+		LapIndex:           uint32(index),
+		ElapsedTime:        uint32(value(fields.TotalElapsedTime, 1000) + 0.5),
+		MovingTime:         uint32(value(fields.TotalTimerTime, 1000) + 0.5),
+		Distance:           value(fields.TotalDistance, 100),
+		TotalElevationGain: value(fields.TotalAscent, 1),
+		AverageSpeed:       value(fields.AvgSpeed, 1000),
+		MaxSpeed:           value(fields.MaxSpeed, 1000),
+		AverageCadence:     value(fields.AvgCadence, 1),
+		AverageWatts:       value(fields.AvgPower, 1),
+		AverageHeartrate:   value(fields.AvgHeartRate, 1),
+		MaxHeartrate:       value(fields.MaxHeartRate, 1),
+	}
+	if index > 0 {
+		lap.Name = fmt.Sprintf("Lap %d", index)
+	}
+	if _, found := msg.field(fields.EnhancedAvgSpeed); found {
+		lap.AverageSpeed = value(fields.EnhancedAvgSpeed, 1000)
+	}
+	if _, found := msg.field(fields.EnhancedMaxSpeed); found {
+		lap.MaxSpeed = value(fields.EnhancedMaxSpeed, 1000)
+	}
+
+	start, found := msg.field(fields.StartTime)
+	if !found && len(records) > 0 {
+		start, _ = records[0].field(timestampField)
+	}
+	end, found := msg.field(timestampField)
+	if !found {
+		end = start + float64(lap.ElapsedTime)
+	}
+	lap.StartDate = toTime(start)
+	lap.StartDateLocal = lap.StartDate.Add(localOffset)
+
+	first := true
+	for i, record := range records {
+		timestamp, _ := record.field(timestampField)
+		if timestamp < start || timestamp > end {
+			continue
+		}
+		if first {
+			lap.StartIndex = uint32(i)
+			first = false
+		}
+		lap.EndIndex = uint32(i)
+	}
+
+	return lap
+}
@@ -0,0 +1,413 @@
+package fit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+)
+
+// Returned for files that are not FIT files, or are truncated or corrupted
+var ErrInvalid = errors.New("invalid FIT file")
+
+const (
+	definitionFlag    = 0x40
+	developerDataFlag = 0x20
+	compressedFlag    = 0x80
+	localTypeMask     = 0x0F
+	timeOffsetMask    = 0x1F
+)
+
+// Base types of fields. The low 5 bits are the type number; the high bit marks multi-byte types.
+const (
+	enumType    = 0x00
+	sint8Type   = 0x01
+	uint8Type   = 0x02
+	sint16Type  = 0x83
+	uint16Type  = 0x84
+	sint32Type  = 0x85
+	uint32Type  = 0x86
+	stringType  = 0x07
+	float32Type = 0x88
+	float64Type = 0x89
+	uint8zType  = 0x0A
+	uint16zType = 0x8B
+	uint32zType = 0x8C
+	byteType    = 0x0D
+	sint64Type  = 0x8E
+	uint64Type  = 0x8F
+	uint64zType = 0x90
+)
+
+// Identifies a developer field, which is described by a field description message
+// before it is used.
+type developerKey struct {
+	DeveloperIndex byte
+	Number         byte
+}
+
+type developerField struct {
+	Name     string
+	Units    string
+	BaseType byte
+}
+
+type fieldDefinition struct {
+	Number   byte
+	Size     byte
+	BaseType byte
+}
+
+type developerFieldDefinition struct {
+	Number         byte
+	Size           byte
+	DeveloperIndex byte
+}
+
+// The layout of the data messages of a local message type.
+type definition struct {
+	ByteOrder       binary.ByteOrder
+	Global          uint16
+	Fields          []*fieldDefinition
+	DeveloperFields []*developerFieldDefinition
+}
+
+// A decoded data message. Fields with invalid values, which FIT uses to mean the value
+// is missing, are left out. Only the first value of array fields is kept.
+type message struct {
+	Global    uint16
+	Fields    map[byte]float64
+	Strings   map[byte]string
+	Developer map[developerKey]float64
+}
+
+func (m *message) field(number byte) (float64, bool) {
+	value, found := m.Fields[number]
+	return value, found
+}
+
+// Decodes the messages of a FIT file, which is a header, a sequence of definition and data
+// records, and a CRC. Field descriptions are applied as they are decoded so later
+// developer fields can be read.
+type decoder struct {
+	body            []byte
+	pos             int
+	definitions     [localTypeMask + 1]*definition
+	developerFields map[developerKey]*developerField
+	lastTimestamp   uint32
+}
+
+func decodeMessages(r io.Reader) ([]*message, map[developerKey]*developerField, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := checkFile(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d := &decoder{body: body, developerFields: make(map[developerKey]*developerField)}
+	messages := make([]*message, 0)
+	for d.pos < len(d.body) {
+		msg, err := d.record()
+		if err != nil {
+			return nil, nil, err
+		}
+		if msg != nil {
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages, d.developerFields, nil
+}
+
+// Validate the header and CRC of a file, returning its records.
+func checkFile(data []byte) ([]byte, error) {
+	if len(data) < 12 {
+		return nil, ErrInvalid
+	}
+
+	headerSize := int(data[0])
+	if headerSize < 12 || len(data) < headerSize || string(data[8:12]) != ".FIT" {
+		return nil, ErrInvalid
+	}
+	if headerSize >= 14 {
+		// A header CRC of 0 means it wasn't computed
+		if headerCrc := binary.LittleEndian.Uint16(data[12:14]); headerCrc != 0 && headerCrc != crc(data[:12]) {
+			return nil, fmt.Errorf("%s: header CRC mismatch", ErrInvalid)
+		}
+	}
+
+	dataSize := int(binary.LittleEndian.Uint32(data[4:8]))
+	end := headerSize + dataSize
+	if len(data) < end+2 {
+		return nil, fmt.Errorf("%s: truncated", ErrInvalid)
+	}
+	if binary.LittleEndian.Uint16(data[end:end+2]) != crc(data[:end]) {
+		return nil, fmt.Errorf("%s: CRC mismatch", ErrInvalid)
+	}
+
+	return data[headerSize:end], nil
+}
+
+// Read one record. Definition records return a nil message.
+func (d *decoder) record() (*message, error) {
+	header, err := d.bytes(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if header[0]&compressedFlag != 0 {
+		// Compressed timestamp header: the local type is in bits 5-6, and the low 5 bits
+		// replace those of the last timestamp, rolling over if they are smaller
+		localType := (header[0] >> 5) & 0x03
+		offset := uint32(header[0] & timeOffsetMask)
+		timestamp := (d.lastTimestamp &^ timeOffsetMask) + offset
+		if offset < d.lastTimestamp&timeOffsetMask {
+			timestamp += timeOffsetMask + 1
+		}
+
+		msg, err := d.data(localType)
+		if err != nil {
+			return nil, err
+		}
+		msg.Fields[timestampField] = float64(timestamp)
+		d.lastTimestamp = timestamp
+		return msg, nil
+	}
+
+	localType := header[0] & localTypeMask
+	if header[0]&definitionFlag != 0 {
+		return nil, d.definition(localType, header[0]&developerDataFlag != 0)
+	}
+
+	msg, err := d.data(localType)
+	if err != nil {
+		return nil, err
+	}
+	if timestamp, found := msg.field(timestampField); found {
+		d.lastTimestamp = uint32(timestamp)
+	}
+	return msg, nil
+}
+
+func (d *decoder) definition(localType byte, hasDeveloperFields bool) error {
+	fixed, err := d.bytes(5)
+	if err != nil {
+		return err
+	}
+
+	def := &definition{ByteOrder: binary.ByteOrder(binary.LittleEndian)}
+	if fixed[1] == 1 {
+		def.ByteOrder = binary.BigEndian
+	}
+	def.Global = def.ByteOrder.Uint16(fixed[2:4])
+
+	fields, err := d.bytes(3 * int(fixed[4]))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(fields); i += 3 {
+		def.Fields = append(def.Fields, &fieldDefinition{Number: fields[i], Size: fields[i+1], BaseType: fields[i+2]})
+	}
+
+	if hasDeveloperFields {
+		count, err := d.bytes(1)
+		if err != nil {
+			return err
+		}
+		fields, err := d.bytes(3 * int(count[0]))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(fields); i += 3 {
+			def.DeveloperFields = append(def.DeveloperFields,
+				&developerFieldDefinition{Number: fields[i], Size: fields[i+1], DeveloperIndex: fields[i+2]})
+		}
+	}
+
+	d.definitions[localType] = def
+	return nil
+}
+
+func (d *decoder) data(localType byte) (*message, error) {
+	def := d.definitions[localType]
+	if def == nil {
+		return nil, fmt.Errorf("%s: data message for undefined local type %d", ErrInvalid, localType)
+	}
+
+	msg := &message{
+		Global:    def.Global,
+		Fields:    make(map[byte]float64),
+		Strings:   make(map[byte]string),
+		Developer: make(map[developerKey]float64),
+	}
+
+	for _, field := range def.Fields {
+		raw, err := d.bytes(int(field.Size))
+		if err != nil {
+			return nil, err
+		}
+		if field.BaseType == stringType {
+			if value := decodeString(raw); value != "" {
+				msg.Strings[field.Number] = value
+			}
+		} else if value, ok := decodeValue(raw, field.BaseType, def.ByteOrder); ok {
+			msg.Fields[field.Number] = value
+		}
+	}
+
+	for _, field := range def.DeveloperFields {
+		raw, err := d.bytes(int(field.Size))
+		if err != nil {
+			return nil, err
+		}
+
+		// Fields without a description can't be interpreted, so they are skipped
+		key := developerKey{DeveloperIndex: field.DeveloperIndex, Number: field.Number}
+		if description, found := d.developerFields[key]; found {
+			if value, ok := decodeValue(raw, description.BaseType, def.ByteOrder); ok {
+				msg.Developer[key] = value
+			}
+		}
+	}
+
+	if msg.Global == fieldDescriptionMessage {
+		d.describe(msg)
+	}
+
+	return msg, nil
+}
+
+// Register the developer field described by a field description message.
+func (d *decoder) describe(msg *message) {
+	developerIndex, found := msg.field(fieldDescriptionDeveloperIndex)
+	if !found {
+		return
+	}
+	number, found := msg.field(fieldDescriptionNumber)
+	if !found {
+		return
+	}
+	baseType, found := msg.field(fieldDescriptionBaseType)
+	if !found {
+		return
+	}
+
+	key := developerKey{DeveloperIndex: byte(developerIndex), Number: byte(number)}
+	d.developerFields[key] = &developerField{
+		Name:     msg.Strings[fieldDescriptionName],
+		Units:    msg.Strings[fieldDescriptionUnits],
+		BaseType: byte(baseType),
+	}
+}
+
+func (d *decoder) bytes(n int) ([]byte, error) {
+	if d.pos+n > len(d.body) {
+		return nil, fmt.Errorf("%s: truncated record", ErrInvalid)
+	}
+	b := d.body[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func decodeString(raw []byte) string {
+	if i := strings.IndexByte(string(raw), 0); i >= 0 {
+		raw = raw[:i]
+	}
+	return string(raw)
+}
+
+// Decode the first value of a field. Returns false if the value is invalid, the type is
+// unknown, or the field is too small for its type.
+func decodeValue(raw []byte, baseType byte, order binary.ByteOrder) (float64, bool) {
+	switch baseType {
+	case enumType, uint8Type, byteType:
+		if len(raw) < 1 || raw[0] == 0xFF {
+			return 0, false
+		}
+		return float64(raw[0]), true
+	case uint8zType:
+		if len(raw) < 1 || raw[0] == 0 {
+			return 0, false
+		}
+		return float64(raw[0]), true
+	case sint8Type:
+		if len(raw) < 1 || raw[0] == 0x7F {
+			return 0, false
+		}
+		return float64(int8(raw[0])), true
+	case uint16Type, uint16zType, sint16Type:
+		if len(raw) < 2 {
+			return 0, false
+		}
+		v := order.Uint16(raw)
+		switch {
+		case baseType == uint16Type && v == 0xFFFF, baseType == uint16zType && v == 0, baseType == sint16Type && v == 0x7FFF:
+			return 0, false
+		case baseType == sint16Type:
+			return float64(int16(v)), true
+		}
+		return float64(v), true
+	case uint32Type, uint32zType, sint32Type:
+		if len(raw) < 4 {
+			return 0, false
+		}
+		v := order.Uint32(raw)
+		switch {
+		case baseType == uint32Type && v == 0xFFFFFFFF, baseType == uint32zType && v == 0, baseType == sint32Type && v == 0x7FFFFFFF:
+			return 0, false
+		case baseType == sint32Type:
+			return float64(int32(v)), true
+		}
+		return float64(v), true
+	case uint64Type, uint64zType, sint64Type:
+		if len(raw) < 8 {
+			return 0, false
+		}
+		v := order.Uint64(raw)
+		switch {
+		case baseType == uint64Type && v == math.MaxUint64, baseType == uint64zType && v == 0, baseType == sint64Type && v == math.MaxInt64:
+			return 0, false
+		case baseType == sint64Type:
+			return float64(int64(v)), true
+		}
+		return float64(v), true
+	case float32Type:
+		if len(raw) < 4 || order.Uint32(raw) == 0xFFFFFFFF {
+			return 0, false
+		}
+		return float64(math.Float32frombits(order.Uint32(raw))), true
+	case float64Type:
+		if len(raw) < 8 || order.Uint64(raw) == math.MaxUint64 {
+			return 0, false
+		}
+		return math.Float64frombits(order.Uint64(raw)), true
+	}
+	return 0, false
+}
+
+var crcTable = [16]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
+}
+
+// The CRC-16 used by FIT, computed a nibble at a time.
+func crc(data []byte) uint16 {
+	var sum uint16
+	for _, b := range data {
+		tmp := crcTable[sum&0xF]
+		sum = (sum >> 4) & 0x0FFF
+		sum = sum ^ tmp ^ crcTable[b&0xF]
+
+		tmp = crcTable[sum&0xF]
+		sum = (sum >> 4) & 0x0FFF
+		sum = sum ^ tmp ^ crcTable[(b>>4)&0xF]
+	}
+	return sum
+}
@@ -0,0 +1,219 @@
+package fit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// Builds FIT files record by record
+type testFile struct {
+	records bytes.Buffer
+}
+
+func (f *testFile) define(local byte, order binary.ByteOrder, global uint16, fields [][3]byte, developerFields [][3]byte) {
+	header := definitionFlag | local
+	if developerFields != nil {
+		header |= developerDataFlag
+	}
+	arch := byte(0)
+	if order == binary.BigEndian {
+		arch = 1
+	}
+
+	f.records.WriteByte(header)
+	f.records.Write([]byte{0, arch})
+	binary.Write(&f.records, order, global)
+	f.records.WriteByte(byte(len(fields)))
+	for _, field := range fields {
+		f.records.Write(field[:])
+	}
+	if developerFields != nil {
+		f.records.WriteByte(byte(len(developerFields)))
+		for _, field := range developerFields {
+			f.records.Write(field[:])
+		}
+	}
+}
+
+func (f *testFile) data(header byte, order binary.ByteOrder, values ...interface{}) {
+	f.records.WriteByte(header)
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			f.records.WriteString(s)
+		} else {
+			binary.Write(&f.records, order, value)
+		}
+	}
+}
+
+func (f *testFile) bytes() []byte {
+	header := make([]byte, 14)
+	header[0] = 14
+	header[1] = 0x20
+	binary.LittleEndian.PutUint16(header[2:4], 2093)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(f.records.Len()))
+	copy(header[8:12], ".FIT")
+	binary.LittleEndian.PutUint16(header[12:14], crc(header[:12]))
+
+	file := append(header, f.records.Bytes()...)
+	sum := make([]byte, 2)
+	binary.LittleEndian.PutUint16(sum, crc(file))
+	return append(file, sum...)
+}
+
+const testStart = 1000000000 // FIT timestamp
+
+func testActivityFile() *testFile {
+	le, be := binary.LittleEndian, binary.BigEndian
+	f := &testFile{}
+
+	// A running power meter's power developer field
+	f.define(0, le, 207, [][3]byte{{3, 1, uint8Type}}, nil)
+	f.data(0, le, uint8(0))
+	f.define(0, le, fieldDescriptionMessage, [][3]byte{{0, 1, uint8Type}, {1, 1, uint8Type}, {2, 1, uint8Type}, {3, 8, stringType}, {8, 4, stringType}}, nil)
+	f.data(0, le, uint8(0), uint8(0), uint8(uint16Type), "Power\x00\x00\x00", "W\x00\x00\x00")
+
+	recordFields := [][3]byte{{0, 4, sint32Type}, {1, 4, sint32Type}, {5, 4, uint32Type}, {78, 4, uint32Type}, {3, 1, uint8Type}}
+	powerField := [][3]byte{{0, 2, 0}}
+	f.define(1, be, recordMessage, append([][3]byte{{timestampField, 4, uint32Type}}, recordFields...), powerField)
+	f.data(1, be, uint32(testStart), int32(447392427), int32(-1455520165), uint32(0), uint32(3000), uint8(120), uint16(250))
+
+	// Compressed timestamps, the second rolling over the 5 bit offset
+	f.define(2, be, recordMessage, recordFields, powerField)
+	f.data(compressedFlag|2<<5|5, be, int32(0x7FFFFFFF), int32(0x7FFFFFFF), uint32(5000), uint32(3010), uint8(125), uint16(0xFFFF))
+	f.data(compressedFlag|2<<5|2, be, int32(447402427), int32(-1455510165), uint32(20000), uint32(3020), uint8(130), uint16(300))
+
+	f.define(3, le, lapMessage, [][3]byte{
+		{timestampField, 4, uint32Type}, {2, 4, uint32Type}, {7, 4, uint32Type}, {8, 4, uint32Type}, {9, 4, uint32Type},
+		{13, 2, uint16Type}, {14, 2, uint16Type}, {21, 2, uint16Type}, {15, 1, uint8Type},
+	}, nil)
+	f.data(3, le, uint32(testStart+34), uint32(testStart), uint32(34000), uint32(30000), uint32(20000),
+		uint16(6667), uint16(10000), uint16(4), uint8(125))
+
+	f.define(3, le, sessionMessage, [][3]byte{
+		{timestampField, 4, uint32Type}, {2, 4, uint32Type}, {5, 1, enumType}, {6, 1, enumType}, {7, 4, uint32Type},
+		{8, 4, uint32Type}, {9, 4, uint32Type}, {22, 2, uint16Type}, {15, 2, uint16Type}, {125, 4, uint32Type},
+	}, nil)
+	f.data(3, le, uint32(testStart+34), uint32(testStart), uint8(2), uint8(virtualSubSport), uint32(34000),
+		uint32(30000), uint32(20000), uint16(4), uint16(0xFFFF), uint32(10500))
+
+	f.define(0, le, activityMessage, [][3]byte{{timestampField, 4, uint32Type}, {activityLocalTimestamp, 4, uint32Type}}, nil)
+	f.data(0, le, uint32(testStart+40), uint32(testStart+40-7*3600))
+
+	return f
+}
+
+func TestDecode(t *testing.T) {
+	file, err := Decode(bytes.NewReader(testActivityFile().bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error for Decode. error=%s", err)
+	}
+
+	start := fitEpoch.Add(testStart * time.Second)
+	expectedLap := &model.Lap{
+		Name:               "Lap 1",
+		LapIndex:           1,
+		StartDate:          start,
+		StartDateLocal:     start.Add(-7 * time.Hour),
+		ElapsedTime:        34,
+		MovingTime:         30,
+		StartIndex:         0,
+		EndIndex:           2,
+		Distance:           200,
+		TotalElevationGain: 4,
+		AverageSpeed:       6.667,
+		MaxSpeed:           10,
+		AverageHeartrate:   125,
+	}
+	expectedActivity := &model.Activity{
+		Type:               "VirtualRide",
+		StartDate:          start,
+		StartDateLocal:     start.Add(-7 * time.Hour),
+		MovingTime:         30,
+		ElapsedTime:        34,
+		Distance:           200,
+		TotalElevationGain: 4,
+		AverageSpeed:       float32(200) / 30,
+		MaxSpeed:           10.5,
+		Laps:               []*model.Lap{expectedLap},
+	}
+	if !reflect.DeepEqual(expectedActivity, file.Activity) {
+		t.Fatalf("Activity was not as expected. expected=%+v, actual=%+v", expectedActivity, file.Activity)
+	}
+	if !reflect.DeepEqual(expectedLap, file.Activity.Laps[0]) {
+		t.Fatalf("Lap was not as expected. expected=%+v, actual=%+v", expectedLap, file.Activity.Laps[0])
+	}
+
+	lat1, lng1 := 447392427*semicirclesToDegrees, -1455520165*semicirclesToDegrees
+	lat2, lng2 := 447402427*semicirclesToDegrees, -1455510165*semicirclesToDegrees
+	expectedStreams := &model.Streams{
+		Time:      []uint32{0, 5, 34},
+		LatLng:    [][2]float64{{lat1, lng1}, {lat1, lng1}, {lat2, lng2}},
+		Distance:  []float32{0, 50, 200},
+		Altitude:  []float32{100, 102, 104},
+		Heartrate: []float32{120, 125, 130},
+		Watts:     []float32{250, 0, 300},
+	}
+	if !reflect.DeepEqual(expectedStreams, file.Streams) {
+		t.Fatalf("Streams were not as expected. expected=%+v, actual=%+v", expectedStreams, file.Streams)
+	}
+	if math.Abs(lat1-37.5) > 0.000001 {
+		t.Fatalf("Expected latitude 37.5 but got %f", lat1)
+	}
+
+	expectedDeveloper := map[string][]float32{"Power": {250, 0, 300}}
+	if !reflect.DeepEqual(expectedDeveloper, file.DeveloperStreams) {
+		t.Fatalf("Developer streams were not as expected. expected=%v, actual=%v", expectedDeveloper, file.DeveloperStreams)
+	}
+}
+
+func TestDecode_WithoutSessions(t *testing.T) {
+	le := binary.LittleEndian
+	f := &testFile{}
+	f.define(0, le, recordMessage, [][3]byte{{timestampField, 4, uint32Type}, {5, 4, uint32Type}, {7, 2, uint16Type}}, nil)
+	f.data(0, le, uint32(testStart), uint32(0), uint16(200))
+	f.data(0, le, uint32(testStart+10), uint32(10000), uint16(210))
+
+	file, err := Decode(bytes.NewReader(f.bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error for Decode. error=%s", err)
+	}
+
+	activity := file.Activity
+	if activity.Type != "" || activity.ElapsedTime != 10 || activity.MovingTime != 10 || activity.Distance != 100 || activity.AverageSpeed != 10 {
+		t.Fatalf("Activity was not as expected. activity=%+v", activity)
+	}
+	if !reflect.DeepEqual(file.Streams.Watts, []float32{200, 210}) || file.Streams.LatLng != nil {
+		t.Fatalf("Streams were not as expected. streams=%+v", file.Streams)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	valid := testActivityFile().bytes()
+
+	corrupted := append([]byte{}, valid...)
+	corrupted[20] ^= 0xFF
+
+	undefined := &testFile{}
+	undefined.data(5, binary.LittleEndian, uint8(1))
+
+	cases := map[string][]byte{
+		"empty":     {},
+		"not FIT":   []byte("<gpx></gpx> is not a FIT file"),
+		"truncated": valid[:len(valid)-10],
+		"corrupted": corrupted,
+		"undefined": undefined.bytes(),
+	}
+	for name, data := range cases {
+		if _, err := Decode(bytes.NewReader(data)); err == nil || !strings.HasPrefix(err.Error(), ErrInvalid.Error()) {
+			t.Fatalf("Expected ErrInvalid for %s file but got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/store"
+)
+
+// Distance and time attributed to a piece of gear.
+type gearUsage struct {
+	Gear          *model.Gear
+	ActivityCount int
+	Distance      float64 // Meters
+	MovingTime    uint64  // Seconds
+	ElapsedTime   uint64  // Seconds
+}
+
+// Service intervals by gear id, in kilometers. Implements flag.Value so it can be given multiple times.
+type serviceIntervals map[model.GearId]float64
+
+func (s serviceIntervals) String() string {
+	intervals := make([]string, 0, len(s))
+	for gearId, km := range s {
+		intervals = append(intervals, fmt.Sprintf("%s=%g", gearId, km))
+	}
+	sort.Strings(intervals)
+	return strings.Join(intervals, ",")
+}
+
+func (s serviceIntervals) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("service interval must be of the form gearId=km: %s", value)
+	}
+
+	km, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || km <= 0 {
+		return fmt.Errorf("service interval must be a positive number of kilometers: %s", value)
+	}
+
+	s[model.GearId(parts[0])] = km
+	return nil
+}
+
+// Prints the distance and time of activities per piece of gear, warning about gear past
+// its service interval.
+func gearReportMain(flags *flag.FlagSet, args []string) error {
+	intervals := make(serviceIntervals)
+
+	accessTokenFlag := addAccessTokenFlag(flags)
+	afterFlag := flags.Int("afterId", 0, "beginning activity id, exclusive")
+	flags.Var(intervals, "service", "service interval as gearId=km, warning once exceeded; may be repeated")
+	storeFlags := addStoreFlags(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *storeFlags.cacheDir); err != nil {
+		return err
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	summaries, err := source.GetActivitySummaries(model.ActivityId(*afterFlag))
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	usages, err := gearUsages(source, summaries)
+	if err != nil {
+		return fmt.Errorf("getting gear: %s", err)
+	}
+
+	for _, usage := range usages {
+		km, found := intervals[usage.Gear.Id]
+		if found && usage.Distance/1000 >= km {
+			fmt.Fprintf(os.Stderr, "Warning: %s (%s) has %.1f km, past its service interval of %g km\n",
+				usage.Gear.Name, usage.Gear.Id, usage.Distance/1000, km)
+		}
+	}
+
+	return outputFlags.print(gearUsageTable(usages))
+}
+
+// Attribute activities to the gear used for them, ordered by gear id.
+// Activities without gear are not included.
+func gearUsages(source store.Source, summaries []*model.ActivitySummary) ([]*gearUsage, error) {
+	usageMap := make(map[model.GearId]*gearUsage)
+	for _, summary := range summaries {
+		if summary.GearId == "" {
+			continue
+		}
+
+		usage, found := usageMap[summary.GearId]
+		if !found {
+			usage = &gearUsage{Gear: &model.Gear{Id: summary.GearId}}
+			usageMap[summary.GearId] = usage
+		}
+
+		usage.ActivityCount++
+		usage.Distance += float64(summary.Distance)
+		usage.MovingTime += uint64(summary.MovingTime)
+		usage.ElapsedTime += uint64(summary.ElapsedTime)
+	}
+
+	usages := make([]*gearUsage, 0, len(usageMap))
+	for gearId, usage := range usageMap {
+		gear, err := source.GetGear(gearId)
+		if err != nil {
+			return nil, err
+		}
+		usage.Gear = gear
+		usages = append(usages, usage)
+	}
+
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Gear.Id < usages[j].Gear.Id })
+
+	return usages, nil
+}
+
+func gearUsageTable(usages []*gearUsage) *table {
+	t := newTable("id", "name", "brand_name", "model_name", "activity_count", "distance", "moving_time", "elapsed_time", "retired")
+	for _, usage := range usages {
+		t.add(usage.Gear.Id, usage.Gear.Name, usage.Gear.BrandName, usage.Gear.ModelName, usage.ActivityCount,
+			distance(usage.Distance), duration(usage.MovingTime), duration(usage.ElapsedTime), usage.Gear.Retired)
+	}
+	return t
+}
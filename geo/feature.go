@@ -0,0 +1,104 @@
+package geo
+
+import (
+	"math"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+const earthRadius = 6371000 // Meters
+
+// A named track with properties, exported as a GeoJSON Feature or KML Placemark.
+type Feature struct {
+	Name       string
+	Track      [][2]float64 // [latitude, longitude]
+	Properties map[string]interface{}
+}
+
+// An activity as a feature with its id, name, type, distance in meters and start date.
+func ActivityFeature(summary *model.ActivitySummary, track [][2]float64) *Feature {
+	return &Feature{
+		Name:  summary.Name,
+		Track: track,
+		Properties: map[string]interface{}{
+			"id":       summary.Id,
+			"name":     summary.Name,
+			"type":     summary.Type,
+			"distance": summary.Distance,
+			"date":     summary.StartDate.Format(time.RFC3339),
+		},
+	}
+}
+
+// A segment as a feature with its id, name, distance in meters, average grade and climb category.
+func SegmentFeature(segment *model.Segment, track [][2]float64) *Feature {
+	return &Feature{
+		Name:  segment.Name,
+		Track: track,
+		Properties: map[string]interface{}{
+			"id":             segment.Id,
+			"name":           segment.Name,
+			"distance":       segment.Distance,
+			"average_grade":  segment.AverageGrade,
+			"climb_category": segment.ClimbCategory,
+		},
+	}
+}
+
+// Simplify a track with the Douglas-Peucker algorithm, keeping only the points needed for
+// the result to stay within tolerance meters of the original. The first and last points are
+// always kept.
+func Simplify(track [][2]float64, tolerance float64) [][2]float64 {
+	if len(track) < 3 || tolerance <= 0 {
+		return track
+	}
+
+	keep := make([]bool, len(track))
+	keep[0], keep[len(track)-1] = true, true
+
+	// Ranges still to simplify, kept on a stack rather than recursing so long tracks are safe
+	stack := [][2]int{{0, len(track) - 1}}
+	for len(stack) > 0 {
+		first, last := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+
+		farthest, maxDistance := -1, tolerance
+		for i := first + 1; i < last; i++ {
+			if d := distanceToSegment(track[i], track[first], track[last]); d > maxDistance {
+				farthest, maxDistance = i, d
+			}
+		}
+
+		if farthest >= 0 {
+			keep[farthest] = true
+			stack = append(stack, [2]int{first, farthest}, [2]int{farthest, last})
+		}
+	}
+
+	simplified := make([][2]float64, 0)
+	for i, point := range track {
+		if keep[i] {
+			simplified = append(simplified, point)
+		}
+	}
+	return simplified
+}
+
+// Meters from a point to the closest point of the segment from a to b, on a flat
+// projection around a, which is accurate over the distances between track points.
+func distanceToSegment(point [2]float64, a [2]float64, b [2]float64) float64 {
+	scale := math.Cos(a[0] * math.Pi / 180)
+	toXY := func(p [2]float64) (float64, float64) {
+		return (p[1] - a[1]) * scale * math.Pi / 180 * earthRadius, (p[0] - a[0]) * math.Pi / 180 * earthRadius
+	}
+
+	px, py := toXY(point)
+	bx, by := toXY(b)
+
+	t := 0.0
+	if lengthSquared := bx*bx + by*by; lengthSquared > 0 {
+		t = math.Max(0, math.Min(1, (px*bx+py*by)/lengthSquared))
+	}
+	return math.Hypot(px-t*bx, py-t*by)
+}
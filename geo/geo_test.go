@@ -0,0 +1,99 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+var testTrack = [][2]float64{{37.8, -122.4}, {37.801, -122.401}}
+
+func TestSimplify(t *testing.T) {
+	// A straight line east along the equator with a 5m wiggle, then a 100m detour north.
+	// 0.0001 degrees is about 11m.
+	track := [][2]float64{{0, 0}, {0.00004, 0.001}, {0, 0.002}, {0.0009, 0.003}, {0, 0.004}}
+
+	if simplified := Simplify(track, 10); !reflect.DeepEqual(simplified, [][2]float64{{0, 0}, {0, 0.002}, {0.0009, 0.003}, {0, 0.004}}) {
+		t.Fatalf("Simplified track was not as expected. actual=%v", simplified)
+	}
+	if simplified := Simplify(track, 1); len(simplified) != len(track) {
+		t.Fatalf("Expected every point to be kept but got %v", simplified)
+	}
+	if simplified := Simplify(track, 200); !reflect.DeepEqual(simplified, [][2]float64{{0, 0}, {0, 0.004}}) {
+		t.Fatalf("Simplified track was not as expected. actual=%v", simplified)
+	}
+}
+
+func TestWriteGeoJson(t *testing.T) {
+	summary := &model.ActivitySummary{Id: 7, Name: "Morning Ride", Type: "Ride", Distance: 1500,
+		StartDate: time.Date(2015, 6, 1, 15, 0, 0, 0, time.UTC)}
+	segment := &model.Segment{Id: 229781, Name: "Hawk Hill", Distance: 2500, AverageGrade: 5.5, ClimbCategory: 1}
+
+	var buf bytes.Buffer
+	if err := WriteGeoJson(&buf, []*Feature{ActivityFeature(summary, testTrack), SegmentFeature(segment, testTrack)}); err != nil {
+		t.Fatalf("Unexpected error for WriteGeoJson. error=%s", err)
+	}
+
+	var actual map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("Unexpected error parsing GeoJSON. error=%s", err)
+	}
+
+	var expected map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"geometry": {"type": "LineString", "coordinates": [[-122.4, 37.8], [-122.401, 37.801]]},
+			"properties": {"id": 7, "name": "Morning Ride", "type": "Ride", "distance": 1500, "date": "2015-06-01T15:00:00Z"}
+		}, {
+			"type": "Feature",
+			"geometry": {"type": "LineString", "coordinates": [[-122.4, 37.8], [-122.401, 37.801]]},
+			"properties": {"id": 229781, "name": "Hawk Hill", "distance": 2500, "average_grade": 5.5, "climb_category": 1}
+		}]
+	}`), &expected)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("GeoJSON was not as expected. expected=%v, actual=%v", expected, actual)
+	}
+}
+
+func TestWriteKml(t *testing.T) {
+	summary := &model.ActivitySummary{Id: 7, Name: "Ride & Coffee", Type: "Ride", Distance: 1500,
+		StartDate: time.Date(2015, 6, 1, 15, 0, 0, 0, time.UTC)}
+
+	var buf bytes.Buffer
+	if err := WriteKml(&buf, "Activities", []*Feature{ActivityFeature(summary, testTrack)}); err != nil {
+		t.Fatalf("Unexpected error for WriteKml. error=%s", err)
+	}
+
+	var doc kml
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Unexpected error parsing KML. error=%s", err)
+	}
+
+	if doc.Document.Name != "Activities" || len(doc.Document.Placemarks) != 1 {
+		t.Fatalf("Document was not as expected. actual=%+v", doc.Document)
+	}
+	placemark := doc.Document.Placemarks[0]
+	if placemark.Name != "Ride & Coffee" {
+		t.Fatalf("Placemark name was not as expected. expected=Ride & Coffee, actual=%s", placemark.Name)
+	}
+	if coordinates := placemark.LineString.Coordinates; coordinates != "-122.4,37.8 -122.401,37.801" {
+		t.Fatalf("Coordinates were not as expected. expected=-122.4,37.8 -122.401,37.801, actual=%s", coordinates)
+	}
+
+	names := make([]string, len(placemark.ExtendedData))
+	for i, data := range placemark.ExtendedData {
+		names[i] = data.Name + "=" + data.Value
+	}
+	expected := "date=2015-06-01T15:00:00Z,distance=1500,id=7,name=Ride & Coffee,type=Ride"
+	if actual := strings.Join(names, ","); actual != expected {
+		t.Fatalf("Extended data was not as expected. expected=%s, actual=%s", expected, actual)
+	}
+}
@@ -0,0 +1,44 @@
+package geo
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type featureCollection struct {
+	Type     string         `json:"type"`
+	Features []*jsonFeature `json:"features"`
+}
+
+type jsonFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   *lineString            `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type lineString struct {
+	Type        string       `json:"type"`
+	Coordinates [][2]float64 `json:"coordinates"` // [longitude, latitude], unlike everywhere else
+}
+
+// Write features as a GeoJSON FeatureCollection of LineStrings.
+// https://tools.ietf.org/html/rfc7946
+func WriteGeoJson(w io.Writer, features []*Feature) error {
+	collection := &featureCollection{Type: "FeatureCollection", Features: make([]*jsonFeature, len(features))}
+	for i, feature := range features {
+		coordinates := make([][2]float64, len(feature.Track))
+		for j, point := range feature.Track {
+			coordinates[j] = [2]float64{point[1], point[0]}
+		}
+
+		collection.Features[i] = &jsonFeature{
+			Type:       "Feature",
+			Geometry:   &lineString{Type: "LineString", Coordinates: coordinates},
+			Properties: feature.Properties,
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(collection)
+}
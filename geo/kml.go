@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type kml struct {
+	XMLName  xml.Name    `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string          `xml:"name"`
+	Placemarks []*kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name         string        `xml:"name"`
+	ExtendedData []*kmlData    `xml:"ExtendedData>Data"`
+	LineString   kmlLineString `xml:"LineString"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlLineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+// Write features as a KML document of Placemarks with LineStrings, with their properties as
+// ExtendedData ordered by name.
+// https://developers.google.com/kml/documentation/kmlreference
+func WriteKml(w io.Writer, name string, features []*Feature) error {
+	doc := &kml{Document: kmlDocument{Name: name, Placemarks: make([]*kmlPlacemark, len(features))}}
+	for i, feature := range features {
+		keys := make([]string, 0, len(feature.Properties))
+		for key := range feature.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		data := make([]*kmlData, len(keys))
+		for j, key := range keys {
+			data[j] = &kmlData{Name: key, Value: fmt.Sprint(feature.Properties[key])}
+		}
+
+		// Coordinates are longitude,latitude tuples separated by spaces
+		coordinates := make([]string, len(feature.Track))
+		for j, point := range feature.Track {
+			coordinates[j] = strconv.FormatFloat(point[1], 'f', -1, 64) + "," + strconv.FormatFloat(point[0], 'f', -1, 64)
+		}
+
+		doc.Document.Placemarks[i] = &kmlPlacemark{
+			Name:         feature.Name,
+			ExtendedData: data,
+			LineString:   kmlLineString{Tessellate: 1, Coordinates: strings.Join(coordinates, " ")},
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
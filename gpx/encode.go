@@ -0,0 +1,105 @@
+package gpx
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const creator = "github.com/alecholmes/strava"
+
+// GPX track types by Strava activity type, for types devices commonly use.
+var activityTypes = map[string]string{
+	"Ride": "cycling",
+	"Run":  "running",
+	"Walk": "walking",
+	"Hike": "hiking",
+	"Swim": "swimming",
+}
+
+type gpxOut struct {
+	XMLName  xml.Name  `xml:"gpx"`
+	Version  string    `xml:"version,attr"`
+	Creator  string    `xml:"creator,attr"`
+	Xmlns    string    `xml:"xmlns,attr"`
+	XmlnsTpx string    `xml:"xmlns:gpxtpx,attr"`
+	Time     time.Time `xml:"metadata>time"`
+	Track    struct {
+		Name   string     `xml:"name,omitempty"`
+		Type   string     `xml:"type,omitempty"`
+		Points []pointOut `xml:"trkseg>trkpt"`
+	} `xml:"trk"`
+}
+
+type pointOut struct {
+	Lat        float64        `xml:"lat,attr"`
+	Lon        float64        `xml:"lon,attr"`
+	Ele        *float32       `xml:"ele,omitempty"`
+	Time       time.Time      `xml:"time"`
+	Extensions *extensionsOut `xml:"extensions,omitempty"`
+}
+
+type extensionsOut struct {
+	Power     *float32 `xml:"power,omitempty"`
+	Extension struct {
+		Temp      *float32 `xml:"gpxtpx:atemp,omitempty"`
+		Heartrate *float32 `xml:"gpxtpx:hr,omitempty"`
+		Cadence   *float32 `xml:"gpxtpx:cad,omitempty"`
+	} `xml:"gpxtpx:TrackPointExtension"`
+}
+
+// Encode an activity's streams as a GPX 1.1 track, with heart rate, cadence and temperature
+// in Garmin's TrackPointExtension. The streams must include positions; samples are timed
+// from the activity's start date.
+func Encode(w io.Writer, file *File) error {
+	out := &gpxOut{
+		Version:  "1.1",
+		Creator:  creator,
+		Xmlns:    "http://www.topografix.com/GPX/1/1",
+		XmlnsTpx: "http://www.garmin.com/xmlschemas/TrackPointExtension/v1",
+		Time:     file.Activity.StartDate.UTC(),
+	}
+	out.Track.Name = file.Activity.Name
+	out.Track.Type = activityTypes[file.Activity.Type]
+
+	streams := file.Streams
+	for i, latLng := range streams.LatLng {
+		point := pointOut{Lat: latLng[0], Lon: latLng[1], Time: out.Time}
+		if i < len(streams.Time) {
+			point.Time = out.Time.Add(time.Duration(streams.Time[i]) * time.Second)
+		}
+		if i < len(streams.Altitude) {
+			point.Ele = &streams.Altitude[i]
+		}
+
+		extensions := &extensionsOut{}
+		if i < len(streams.Heartrate) {
+			extensions.Extension.Heartrate = &streams.Heartrate[i]
+		}
+		if i < len(streams.Cadence) {
+			extensions.Extension.Cadence = &streams.Cadence[i]
+		}
+		if i < len(streams.Temp) {
+			extensions.Extension.Temp = &streams.Temp[i]
+		}
+		if i < len(streams.Watts) {
+			extensions.Power = &streams.Watts[i]
+		}
+		if extensions.Power != nil || extensions.Extension.Heartrate != nil || extensions.Extension.Cadence != nil || extensions.Extension.Temp != nil {
+			point.Extensions = extensions
+		}
+
+		out.Track.Points = append(out.Track.Points, point)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", " ")
+	if err := encoder.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,116 @@
+package gpx
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/track"
+)
+
+// Elements are matched by local name, so both GPX 1.0 and 1.1 decode, along with Garmin's
+// TrackPointExtension v1 and v2.
+type gpxFile struct {
+	Name   string     `xml:"metadata>name"`
+	Name10 string     `xml:"name"` // GPX 1.0 has no metadata element
+	Tracks []gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name     string `xml:"name"`
+	Type     string `xml:"type"`
+	Segments []struct {
+		Points []gpxPoint `xml:"trkpt"`
+	} `xml:"trkseg"`
+}
+
+type gpxPoint struct {
+	Lat       float64   `xml:"lat,attr"`
+	Lon       float64   `xml:"lon,attr"`
+	Ele       *float32  `xml:"ele"`
+	Time      time.Time `xml:"time"`
+	Heartrate float32   `xml:"extensions>TrackPointExtension>hr"`
+	Cadence   float32   `xml:"extensions>TrackPointExtension>cad"`
+	Temp      *float32  `xml:"extensions>TrackPointExtension>atemp"`
+	Power     float32   `xml:"extensions>power"`
+}
+
+// Strava activity types by GPX track type. Strava writes its own types, and devices
+// commonly write these.
+var trackTypes = map[string]string{
+	"1":        "Ride",
+	"9":        "Run",
+	"10":       "Walk",
+	"4":        "Hike",
+	"biking":   "Ride",
+	"cycling":  "Ride",
+	"ride":     "Ride",
+	"running":  "Run",
+	"run":      "Run",
+	"walking":  "Walk",
+	"walk":     "Walk",
+	"hiking":   "Hike",
+	"hike":     "Hike",
+	"swimming": "Swim",
+	"swim":     "Swim",
+}
+
+// A decoded GPX file.
+type File struct {
+	// Id is 0 since the activity isn't from Strava
+	Activity *model.Activity
+	Streams  *model.Streams
+}
+
+// Decode a GPX 1.0 or 1.1 file. Points of all tracks and segments are combined into one
+// activity, named and typed after the first track. See track.Build for how aggregates are computed.
+func Decode(r io.Reader) (*File, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+
+	points := make([]*track.Point, 0)
+	for _, trk := range file.Tracks {
+		for _, segment := range trk.Segments {
+			for _, p := range segment.Points {
+				points = append(points, toPoint(&p))
+			}
+		}
+	}
+
+	activity, streams := track.Build(points)
+	activity.Name = file.Name
+	if activity.Name == "" {
+		activity.Name = file.Name10
+	}
+	if len(file.Tracks) > 0 {
+		if file.Tracks[0].Name != "" {
+			activity.Name = file.Tracks[0].Name
+		}
+		activity.Type = trackTypes[strings.ToLower(strings.TrimSpace(file.Tracks[0].Type))]
+	}
+
+	return &File{Activity: activity, Streams: streams}, nil
+}
+
+func toPoint(p *gpxPoint) *track.Point {
+	point := &track.Point{
+		Time:      p.Time,
+		Lat:       p.Lat,
+		Lng:       p.Lon,
+		HasLatLng: true,
+		Heartrate: p.Heartrate,
+		Cadence:   p.Cadence,
+		Watts:     p.Power,
+	}
+	if p.Ele != nil {
+		point.Altitude, point.HasAltitude = *p.Ele, true
+	}
+	if p.Temp != nil {
+		point.Temp, point.HasTemp = *p.Temp, true
+	}
+	return point
+}
@@ -0,0 +1,160 @@
+package gpx
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+const gpx11 = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx creator="Garmin Edge 520" version="1.1" xmlns="http://www.topografix.com/GPX/1/1"
+  xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+ <metadata><name>Metadata name</name><time>2018-06-01T21:00:00Z</time></metadata>
+ <trk>
+  <name>Lunch Run</name>
+  <type>running</type>
+  <trkseg>
+   <trkpt lat="37.000" lon="-122.0"><ele>10.0</ele><time>2018-06-01T21:00:00Z</time>
+    <extensions><gpxtpx:TrackPointExtension><gpxtpx:atemp>21.0</gpxtpx:atemp><gpxtpx:hr>140</gpxtpx:hr><gpxtpx:cad>85</gpxtpx:cad></gpxtpx:TrackPointExtension></extensions>
+   </trkpt>
+   <trkpt lat="37.001" lon="-122.0"><ele>12.0</ele><time>2018-06-01T21:00:30Z</time>
+    <extensions><gpxtpx:TrackPointExtension><gpxtpx:atemp>21.0</gpxtpx:atemp><gpxtpx:hr>150</gpxtpx:hr><gpxtpx:cad>88</gpxtpx:cad></gpxtpx:TrackPointExtension></extensions>
+   </trkpt>
+  </trkseg>
+  <trkseg>
+   <trkpt lat="37.002" lon="-122.0"><ele>14.0</ele><time>2018-06-01T21:01:00Z</time>
+    <extensions><gpxtpx:TrackPointExtension><gpxtpx:atemp>20.0</gpxtpx:atemp><gpxtpx:hr>155</gpxtpx:hr><gpxtpx:cad>90</gpxtpx:cad></gpxtpx:TrackPointExtension></extensions>
+   </trkpt>
+  </trkseg>
+ </trk>
+</gpx>`
+
+const gpx10 = `<?xml version="1.0"?>
+<gpx version="1.0" creator="GPSBabel" xmlns="http://www.topografix.com/GPX/1/0">
+ <name>Old Ride</name>
+ <trk><trkseg>
+  <trkpt lat="37.000" lon="-122.000"><time>2010-05-01T15:00:00Z</time><speed>8.0</speed></trkpt>
+  <trkpt lat="37.000" lon="-122.000"><time>2010-05-01T15:01:00Z</time><speed>0.0</speed></trkpt>
+  <trkpt lat="37.000" lon="-121.999"><time>2010-05-01T15:01:10Z</time><speed>8.0</speed></trkpt>
+ </trkseg></trk>
+</gpx>`
+
+func TestDecode_Gpx11(t *testing.T) {
+	file, err := Decode(strings.NewReader(gpx11))
+	if err != nil {
+		t.Fatalf("Unexpected error for Decode. error=%s", err)
+	}
+
+	activity := file.Activity
+	if activity.Name != "Lunch Run" || activity.Type != "Run" || !activity.StartDate.Equal(time.Date(2018, 6, 1, 21, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Activity was not as expected. activity=%+v", activity)
+	}
+	// 0.002 degrees of latitude is about 222 meters
+	if activity.Distance < 221 || activity.Distance > 224 || activity.ElapsedTime != 60 || activity.MovingTime != 60 {
+		t.Fatalf("Activity distance and times were not as expected. activity=%+v", activity)
+	}
+
+	streams := file.Streams
+	if !reflect.DeepEqual(streams.Time, []uint32{0, 30, 60}) ||
+		!reflect.DeepEqual(streams.Altitude, []float32{10, 12, 14}) ||
+		!reflect.DeepEqual(streams.Heartrate, []float32{140, 150, 155}) ||
+		!reflect.DeepEqual(streams.Cadence, []float32{85, 88, 90}) ||
+		!reflect.DeepEqual(streams.Temp, []float32{21, 21, 20}) ||
+		len(streams.LatLng) != 3 || len(streams.Watts) != 0 {
+		t.Fatalf("Streams were not as expected. streams=%+v", streams)
+	}
+}
+
+func TestDecode_Gpx10(t *testing.T) {
+	file, err := Decode(strings.NewReader(gpx10))
+	if err != nil {
+		t.Fatalf("Unexpected error for Decode. error=%s", err)
+	}
+
+	activity := file.Activity
+	// Without a track name or type, the file's name is used and the type is unknown
+	if activity.Name != "Old Ride" || activity.Type != "" {
+		t.Fatalf("Activity was not as expected. activity=%+v", activity)
+	}
+	// Stopped for the first minute
+	if activity.ElapsedTime != 70 || activity.MovingTime != 10 || activity.AverageSpeed != activity.Distance/10 {
+		t.Fatalf("Activity times were not as expected. activity=%+v", activity)
+	}
+	if len(file.Streams.Altitude) != 0 || len(file.Streams.GradeSmooth) != 0 {
+		t.Fatalf("Expected no altitude streams. streams=%+v", file.Streams)
+	}
+}
+
+func TestDecode_Untimed(t *testing.T) {
+	// The first point has no time and the last is earlier than the one before it
+	untimed := `<?xml version="1.0"?>
+<gpx version="1.1" xmlns="http://www.topografix.com/GPX/1/1">
+ <trk><trkseg>
+  <trkpt lat="36.999" lon="-122.000"></trkpt>
+  <trkpt lat="37.000" lon="-122.000"><time>2018-06-01T21:00:00Z</time></trkpt>
+  <trkpt lat="37.001" lon="-122.000"><time>2018-06-01T21:00:30Z</time></trkpt>
+  <trkpt lat="37.002" lon="-122.000"><time>2018-06-01T20:59:00Z</time></trkpt>
+ </trkseg></trk>
+</gpx>`
+
+	file, err := Decode(strings.NewReader(untimed))
+	if err != nil {
+		t.Fatalf("Unexpected error for Decode. error=%s", err)
+	}
+
+	if expected := []uint32{0, 30}; !reflect.DeepEqual(expected, file.Streams.Time) {
+		t.Fatalf("Time stream was not as expected. expected=%v, actual=%v", expected, file.Streams.Time)
+	}
+	activity := file.Activity
+	if !activity.StartDate.Equal(time.Date(2018, 6, 1, 21, 0, 0, 0, time.UTC)) || activity.ElapsedTime != 30 || activity.MovingTime != 30 {
+		t.Fatalf("Activity times were not as expected. activity=%+v", activity)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	if _, err := Decode(strings.NewReader("<gpx><trk>")); err == nil {
+		t.Fatalf("Expected an error for a truncated file")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	start := time.Date(2018, 6, 1, 21, 0, 0, 0, time.UTC)
+	file := &File{
+		Activity: &model.Activity{Name: "Lunch Ride", Type: "Ride", StartDate: start},
+		Streams: &model.Streams{
+			Time:      []uint32{0, 10, 20},
+			LatLng:    [][2]float64{{37, -122}, {37.001, -122}, {37.002, -122}},
+			Altitude:  []float32{10, 11, 12},
+			Heartrate: []float32{120, 130, 140},
+			Watts:     []float32{200, 210, 220},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, file); err != nil {
+		t.Fatalf("Unexpected error for Encode. error=%s", err)
+	}
+
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error decoding encoded file. error=%s", err)
+	}
+	if decoded.Activity.Name != "Lunch Ride" || decoded.Activity.Type != "Ride" || !decoded.Activity.StartDate.Equal(start) {
+		t.Fatalf("Activity was not as expected. activity=%+v", decoded.Activity)
+	}
+	for name, pair := range map[string][2]interface{}{
+		"time":      {file.Streams.Time, decoded.Streams.Time},
+		"latlng":    {file.Streams.LatLng, decoded.Streams.LatLng},
+		"altitude":  {file.Streams.Altitude, decoded.Streams.Altitude},
+		"heartrate": {file.Streams.Heartrate, decoded.Streams.Heartrate},
+		"watts":     {file.Streams.Watts, decoded.Streams.Watts},
+	} {
+		if !reflect.DeepEqual(pair[0], pair[1]) {
+			t.Fatalf("Stream %s did not survive encoding. expected=%v, actual=%v", name, pair[0], pair[1])
+		}
+	}
+}
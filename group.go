@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alecholmes/strava/analysis"
+	"github.com/alecholmes/strava/model"
+)
+
+// Prints the athletes of a group ride side by side, or with --segments how they ranked on
+// each segment they shared.
+func groupRideMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	segmentsFlag := flags.Bool("segments", false, "rank the athletes on each segment at least two of them rode")
+	cacheFlag := addCacheFlag(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return usageErrorf("an activity id is required")
+	}
+	activityId, err := strconv.ParseUint(flags.Arg(0), 10, 64)
+	if err != nil {
+		return usageErrorf("invalid activity id %s", flags.Arg(0))
+	}
+	if *accessTokenFlag == "" {
+		return usageErrorf("--accessToken is required")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *cacheFlag); err != nil {
+		return err
+	}
+
+	stravaClient, err := newClient(*accessTokenFlag, *cacheFlag)
+	if err != nil {
+		return fmt.Errorf("creating client: %s", err)
+	}
+
+	related, err := stravaClient.GetRelatedActivitySummaries(model.ActivityId(activityId))
+	if err != nil {
+		return fmt.Errorf("getting related activities: %s", err)
+	}
+
+	activityIds := []model.ActivityId{model.ActivityId(activityId)}
+	for _, summary := range related {
+		activityIds = append(activityIds, summary.Id)
+	}
+	activities, err := stravaClient.GetActivities(activityIds)
+	if err != nil {
+		return fmt.Errorf("getting activities: %s", err)
+	}
+	activities = fillMissingActivities(activityIds, activities, related)
+
+	if *segmentsFlag {
+		return outputFlags.print(segmentRankingTable(analysis.RankSharedSegments(activities)))
+	}
+	return outputFlags.print(groupTable(activities))
+}
+
+// Activities in the order of activityIds. The client leaves out activities it couldn't get,
+// usually because they are private to another athlete, so those are reported and stood in for
+// by their related summaries, which have no segment efforts.
+func fillMissingActivities(activityIds []model.ActivityId, activities []*model.Activity, related []*model.ActivitySummary) []*model.Activity {
+	fetched := make(map[model.ActivityId]*model.Activity)
+	for _, activity := range activities {
+		fetched[activity.Id] = activity
+	}
+	summaries := make(map[model.ActivityId]*model.ActivitySummary)
+	for _, summary := range related {
+		summaries[summary.Id] = summary
+	}
+
+	filled := make([]*model.Activity, 0, len(activityIds))
+	missing := make([]string, 0)
+	for _, activityId := range activityIds {
+		if activity, found := fetched[activityId]; found {
+			filled = append(filled, activity)
+			continue
+		}
+
+		missing = append(missing, strconv.FormatUint(uint64(activityId), 10))
+		if summary, found := summaries[activityId]; found {
+			filled = append(filled, &model.Activity{
+				Id:                 summary.Id,
+				Name:               summary.Name,
+				Type:               summary.Type,
+				Athlete:            summary.Athlete,
+				StartDate:          summary.StartDate,
+				MovingTime:         summary.MovingTime,
+				ElapsedTime:        summary.ElapsedTime,
+				Distance:           summary.Distance,
+				TotalElevationGain: summary.TotalElevationGain,
+				AverageSpeed:       summary.AverageSpeed,
+			})
+		}
+	}
+
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "Could not get activities %s, which may be private; their segment efforts are left out\n",
+			strings.Join(missing, ", "))
+	}
+	return filled
+}
+
+// The athlete's name, or their id if the activity only includes that.
+func athleteName(activity *model.Activity) string {
+	if activity.Athlete == nil {
+		return ""
+	}
+	if name := strings.TrimSpace(activity.Athlete.FirstName + " " + activity.Athlete.LastName); name != "" {
+		return name
+	}
+	return strconv.FormatInt(int64(activity.Athlete.Id), 10)
+}
+
+func groupTable(activities []*model.Activity) *table {
+	t := newTable("athlete", "activity_id", "activity_name", "distance", "moving_time", "elapsed_time", "average_speed",
+		"total_elevation_gain", "segment_effort_count")
+	for _, activity := range activities {
+		t.add(athleteName(activity), activity.Id, activity.Name, distance(activity.Distance), duration(activity.MovingTime),
+			duration(activity.ElapsedTime), speedOrPace(activity.Type, activity.AverageSpeed), elevation(activity.TotalElevationGain),
+			len(activity.SegmentEfforts))
+	}
+	return t
+}
+
+func segmentRankingTable(rankings []*analysis.SegmentRanking) *table {
+	t := newTable("segment_id", "segment_name", "rank", "athlete", "activity_id", "elapsed_time", "behind")
+	for _, ranking := range rankings {
+		for _, effort := range ranking.Efforts {
+			t.add(ranking.Segment.Id, ranking.Segment.Name, effort.Rank, athleteName(effort.Activity), effort.Activity.Id,
+				duration(effort.Effort.ElapsedTime), duration(effort.Behind))
+		}
+	}
+	return t
+}
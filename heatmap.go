@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecholmes/strava/analysis"
+	"github.com/alecholmes/strava/heatmap"
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/polyline"
+	"github.com/alecholmes/strava/store"
+)
+
+// Renders the tracks of all activities into a heatmap image, without any map tiles.
+func heatmapMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	afterFlag := flags.Int("afterId", 0, "beginning activity id, exclusive")
+	outFlag := flags.String("out", "", "file to write, as .png or .svg; required")
+	widthFlag := flags.Int("width", 1024, "image width in pixels; the height follows from the bounds")
+	boundsFlag := flags.String("bounds", "", "area to render as minLat,minLng,maxLat,maxLng; defaults to the area of all activities")
+	typeFlag := flags.String("type", "", "comma separated activity types to include, such as Ride,Run; all types if not set")
+	rampFlag := flags.String("ramp", "hot", "color ramp: hot, blue, strava, gray, or comma separated #rrggbb colors from least to most used")
+	detailedFlag := flags.Bool("detailed", false, "draw full resolution tracks from location streams rather than summary polylines")
+	storeFlags := addStoreFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	ext := strings.ToLower(filepath.Ext(*outFlag))
+	if ext != ".png" && ext != ".svg" {
+		return usageErrorf("--out is required and must end in .png or .svg")
+	}
+	ramp, err := heatmap.ParseRamp(*rampFlag)
+	if err != nil {
+		return &usageError{message: err.Error()}
+	}
+	if *widthFlag < 1 || *widthFlag > heatmap.MaxSize {
+		return usageErrorf("--width must be between 1 and %d", heatmap.MaxSize)
+	}
+	// Given bounds are checked before fetching anything, including the height that follows from them
+	var h *heatmap.Heatmap
+	if *boundsFlag != "" {
+		bounds, err := heatmap.ParseBounds(*boundsFlag)
+		if err != nil {
+			return &usageError{message: err.Error()}
+		}
+		if h, err = heatmap.New(bounds, *widthFlag); err != nil {
+			return &usageError{message: err.Error()}
+		}
+	}
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	summaries, err := source.GetActivitySummaries(model.ActivityId(*afterFlag))
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	var types []string
+	if *typeFlag != "" {
+		types = strings.Split(*typeFlag, ",")
+	}
+	tracks, err := activityTracks(source, summaries, types, *detailedFlag || *storeFlags.offline, *detailedFlag)
+	if err != nil {
+		return err
+	}
+
+	if h == nil {
+		bounds, found := heatmap.BoundsOf(tracks)
+		if !found {
+			return fmt.Errorf("no activities have tracks")
+		}
+		if h, err = heatmap.New(bounds, *widthFlag); err != nil {
+			return err
+		}
+	}
+	for _, track := range tracks {
+		h.Add(track)
+	}
+
+	return writeHeatmap(*outFlag, h, ramp, ext == ".svg")
+}
+
+// The tracks of activities of the given types. Activities without a track are left out.
+func activityTracks(source store.Source, summaries []*model.ActivitySummary, types []string, useStreams bool, detailed bool) ([][][2]float64, error) {
+	tracks := make([][][2]float64, 0, len(summaries))
+	invalid := 0
+	for _, summary := range summaries {
+		if !analysis.HasType(summary.Type, types) {
+			continue
+		}
+
+		track, err := activityTrack(source, summary, useStreams, detailed)
+		if err == polyline.ErrInvalid {
+			invalid++
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if len(track) > 0 {
+			tracks = append(tracks, track)
+		}
+	}
+
+	if invalid > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d activities with invalid polylines\n", invalid)
+	}
+	return tracks, nil
+}
+
+// The track of an activity, or nil if it has none. The summary polyline is used unless detailed,
+// in which case the location stream is. If useStreams, activities without a polyline, such as
+// imported ones, fall back to their stream. Returns polyline.ErrInvalid for a corrupt polyline.
+func activityTrack(source store.Source, summary *model.ActivitySummary, useStreams bool, detailed bool) ([][2]float64, error) {
+	if !detailed && summary.Map != nil && summary.Map.SummaryPolyline != "" {
+		return polyline.Decode(summary.Map.SummaryPolyline)
+	}
+	if !useStreams {
+		return nil, nil
+	}
+
+	streams, err := source.GetActivityStreams(summary.Id)
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting streams of activity %d: %s", summary.Id, err)
+	}
+	return streams.LatLng, nil
+}
+
+func writeHeatmap(path string, h *heatmap.Heatmap, ramp heatmap.Ramp, svg bool) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	if svg {
+		err = h.WriteSvg(w, ramp)
+	} else {
+		err = h.WritePng(w, ramp)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
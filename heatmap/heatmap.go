@@ -0,0 +1,224 @@
+package heatmap
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A latitude/longitude rectangle to render.
+type Bounds struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// Parse bounds given as minLat,minLng,maxLat,maxLng.
+func ParseBounds(s string) (Bounds, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return Bounds{}, fmt.Errorf("Bounds must be minLat,minLng,maxLat,maxLng: %s", s)
+	}
+
+	values := make([]float64, 4)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return Bounds{}, fmt.Errorf("Invalid bounds %s", s)
+		}
+		values[i] = value
+	}
+
+	bounds := Bounds{MinLat: values[0], MinLng: values[1], MaxLat: values[2], MaxLng: values[3]}
+	if bounds.MinLat >= bounds.MaxLat || bounds.MinLng >= bounds.MaxLng ||
+		bounds.MinLat < -85 || bounds.MaxLat > 85 || bounds.MinLng < -180 || bounds.MaxLng > 180 {
+		return Bounds{}, fmt.Errorf("Invalid bounds %s", s)
+	}
+	return bounds, nil
+}
+
+// The smallest bounds containing every point of the tracks, padded by a small margin.
+// Not ok if there are no points.
+func BoundsOf(tracks [][][2]float64) (Bounds, bool) {
+	bounds := Bounds{MinLat: 90, MinLng: 180, MaxLat: -90, MaxLng: -180}
+	found := false
+	for _, track := range tracks {
+		for _, point := range track {
+			bounds.MinLat = math.Min(bounds.MinLat, point[0])
+			bounds.MinLng = math.Min(bounds.MinLng, point[1])
+			bounds.MaxLat = math.Max(bounds.MaxLat, point[0])
+			bounds.MaxLng = math.Max(bounds.MaxLng, point[1])
+			found = true
+		}
+	}
+	if !found {
+		return Bounds{}, false
+	}
+
+	// Pad by 2%, and enough that a single point still has an area
+	latPad := math.Max((bounds.MaxLat-bounds.MinLat)*0.02, 0.001)
+	lngPad := math.Max((bounds.MaxLng-bounds.MinLng)*0.02, 0.001)
+	bounds.MinLat = math.Max(bounds.MinLat-latPad, -85)
+	bounds.MaxLat = math.Min(bounds.MaxLat+latPad, 85)
+	bounds.MinLng = math.Max(bounds.MinLng-lngPad, -180)
+	bounds.MaxLng = math.Min(bounds.MaxLng+lngPad, 180)
+	return bounds, true
+}
+
+// Counts how many tracks pass through each pixel of a Web Mercator projection of some bounds,
+// the projection used by most web maps.
+type Heatmap struct {
+	bounds Bounds
+	width  int
+	height int
+	counts []uint32 // Row-major, top row first
+	marks  []uint32 // The number of the last track to count each pixel, so tracks count once per pixel
+	tracks uint32
+}
+
+// Largest width or height of a heatmap, in pixels
+const MaxSize = 16384
+
+// A heatmap of the bounds, width pixels wide. The height follows from the projection.
+func New(bounds Bounds, width int) (*Heatmap, error) {
+	xSpan := bounds.MaxLng - bounds.MinLng
+	ySpan := mercatorY(bounds.MaxLat) - mercatorY(bounds.MinLat)
+	height := int(math.Round(float64(width) * ySpan / (xSpan * math.Pi / 180)))
+	if height < 1 {
+		height = 1
+	}
+	if width < 1 || width > MaxSize || height > MaxSize {
+		return nil, fmt.Errorf("Heatmap would be %dx%d; width and height must be at most %d", width, height, MaxSize)
+	}
+
+	return &Heatmap{
+		bounds: bounds,
+		width:  width,
+		height: height,
+		counts: make([]uint32, width*height),
+		marks:  make([]uint32, width*height),
+	}, nil
+}
+
+func (h *Heatmap) Width() int {
+	return h.width
+}
+
+func (h *Heatmap) Height() int {
+	return h.height
+}
+
+// The number of tracks that passed through a pixel.
+func (h *Heatmap) Count(x int, y int) uint32 {
+	return h.counts[y*h.width+x]
+}
+
+// Draw a track of [latitude, longitude] points, counting each pixel it passes through once,
+// however often the track returns to it. Lines between consecutive points are drawn, so
+// sparse tracks such as summary polylines are still continuous. Parts outside the bounds are clipped.
+func (h *Heatmap) Add(track [][2]float64) {
+	h.tracks++
+	for i := range track {
+		x1, y1 := h.project(track[i])
+		x0, y0 := x1, y1
+		if i > 0 {
+			x0, y0 = h.project(track[i-1])
+		}
+		h.line(x0, y0, x1, y1)
+	}
+}
+
+// Pixel coordinates of a point, which may be outside the image.
+func (h *Heatmap) project(point [2]float64) (float64, float64) {
+	x := (point[1] - h.bounds.MinLng) / (h.bounds.MaxLng - h.bounds.MinLng) * float64(h.width)
+	top, bottom := mercatorY(h.bounds.MaxLat), mercatorY(h.bounds.MinLat)
+	y := (top - mercatorY(point[0])) / (top - bottom) * float64(h.height)
+	return x, y
+}
+
+// Mark the pixels along a line by stepping at most a pixel at a time, after clipping it to the image.
+func (h *Heatmap) line(x0 float64, y0 float64, x1 float64, y1 float64) {
+	x0, y0, x1, y1, visible := clip(x0, y0, x1, y1, float64(h.width), float64(h.height))
+	if !visible {
+		return
+	}
+
+	steps := int(math.Ceil(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))))
+	for s := 0; s <= steps; s++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(s) / float64(steps)
+		}
+		h.mark(int(math.Floor(x0+(x1-x0)*t)), int(math.Floor(y0+(y1-y0)*t)))
+	}
+}
+
+// Clip a line to the rectangle from (0, 0) to (width, height) with the Liang-Barsky algorithm.
+// Not visible if the line is entirely outside.
+func clip(x0 float64, y0 float64, x1 float64, y1 float64, width float64, height float64) (float64, float64, float64, float64, bool) {
+	dx, dy := x1-x0, y1-y0
+	t0, t1 := 0.0, 1.0
+	for _, edge := range [][2]float64{{-dx, x0}, {dx, width - x0}, {-dy, y0}, {dy, height - y0}} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			// Parallel to the edge, so either entirely inside or outside it
+			if q < 0 {
+				return 0, 0, 0, 0, false
+			}
+			continue
+		}
+
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return 0, 0, 0, 0, false
+			}
+			t0 = math.Max(t0, r)
+		} else {
+			if r < t0 {
+				return 0, 0, 0, 0, false
+			}
+			t1 = math.Min(t1, r)
+		}
+	}
+
+	return x0 + t0*dx, y0 + t0*dy, x0 + t1*dx, y0 + t1*dy, true
+}
+
+func (h *Heatmap) mark(x int, y int) {
+	if x < 0 || y < 0 || x >= h.width || y >= h.height {
+		return
+	}
+
+	i := y*h.width + x
+	if h.marks[i] != h.tracks {
+		h.marks[i] = h.tracks
+		h.counts[i]++
+	}
+}
+
+// The greatest count of any pixel.
+func (h *Heatmap) max() uint32 {
+	max := uint32(0)
+	for _, count := range h.counts {
+		if count > max {
+			max = count
+		}
+	}
+	return max
+}
+
+// Intensity of a pixel in [0, 1], on a log scale so pixels only a few tracks pass through
+// still show next to those of a daily commute.
+func (h *Heatmap) intensity(count uint32, max uint32) float64 {
+	if count == 0 || max == 0 {
+		return 0
+	}
+	return math.Log(1+float64(count)) / math.Log(1+float64(max))
+}
+
+func mercatorY(lat float64) float64 {
+	return math.Log(math.Tan(math.Pi/4 + lat*math.Pi/360))
+}
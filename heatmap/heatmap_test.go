@@ -0,0 +1,136 @@
+package heatmap
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// Bounds around the equator and prime meridian, where Web Mercator is nearly linear
+var testBounds = Bounds{MinLat: -0.5, MinLng: 0, MaxLat: 0.5, MaxLng: 1}
+
+func TestHeatmap_Add(t *testing.T) {
+	h, err := New(testBounds, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error for New. error=%s", err)
+	}
+	if h.Width() != 10 || h.Height() != 10 {
+		t.Fatalf("Size was not as expected. expected=10x10, actual=%dx%d", h.Width(), h.Height())
+	}
+
+	// A horizontal line across the middle row, ridden out and back
+	h.Add([][2]float64{{0.01, 0.05}, {0.01, 0.95}, {0.01, 0.05}})
+	// A line from the middle of the top edge to well outside the right edge
+	h.Add([][2]float64{{0.45, 0.55}, {0.45, 5}})
+
+	for x := 0; x < 10; x++ {
+		if count := h.Count(x, 4); count != 1 {
+			t.Fatalf("Count of (%d, 4) was not as expected. expected=1, actual=%d", x, count)
+		}
+	}
+	for x := 0; x < 10; x++ {
+		expected := uint32(0)
+		if x >= 5 {
+			expected = 1
+		}
+		if count := h.Count(x, 0); count != expected {
+			t.Fatalf("Count of (%d, 0) was not as expected. expected=%d, actual=%d", x, expected, count)
+		}
+	}
+	if count := h.Count(0, 9); count != 0 {
+		t.Fatalf("Count of (0, 9) was not as expected. expected=0, actual=%d", count)
+	}
+
+	// Another track doubles the count
+	h.Add([][2]float64{{0.01, 0.05}, {0.01, 0.15}})
+	if count := h.Count(0, 4); count != 2 {
+		t.Fatalf("Count of (0, 4) was not as expected. expected=2, actual=%d", count)
+	}
+
+	if _, err := New(Bounds{MinLat: 0, MinLng: 0, MaxLat: 10, MaxLng: 0.001}, 1000); err == nil {
+		t.Fatalf("Expected an error for a heatmap taller than MaxSize")
+	}
+}
+
+func TestBounds(t *testing.T) {
+	bounds, err := ParseBounds("37.7, -122.5, 37.9, -122.3")
+	if err != nil {
+		t.Fatalf("Unexpected error for ParseBounds. error=%s", err)
+	}
+	if bounds != (Bounds{MinLat: 37.7, MinLng: -122.5, MaxLat: 37.9, MaxLng: -122.3}) {
+		t.Fatalf("Bounds were not as expected. actual=%v", bounds)
+	}
+	if _, err := ParseBounds("37.9,-122.5,37.7,-122.3"); err == nil {
+		t.Fatalf("Expected an error for inverted bounds")
+	}
+
+	bounds, ok := BoundsOf([][][2]float64{{{10, 20}, {11, 22}}, {{9, 21}}})
+	expected := Bounds{MinLat: 9 - 0.04, MinLng: 20 - 0.04, MaxLat: 11 + 0.04, MaxLng: 22 + 0.04}
+	if !ok || !closeBounds(bounds, expected) {
+		t.Fatalf("Bounds were not as expected. expected=%v, actual=%v", expected, bounds)
+	}
+	if _, ok := BoundsOf(nil); ok {
+		t.Fatalf("Expected no bounds without points")
+	}
+}
+
+func TestRamp(t *testing.T) {
+	ramp, err := ParseRamp("#000000,#ff8000")
+	if err != nil {
+		t.Fatalf("Unexpected error for ParseRamp. error=%s", err)
+	}
+	if c := ramp.At(0.5); c != (color.RGBA{0x80, 0x40, 0, 0xff}) {
+		t.Fatalf("Color was not as expected. expected=%v, actual=%v", color.RGBA{0x80, 0x40, 0, 0xff}, c)
+	}
+	if _, err := ParseRamp("rainbow"); err == nil {
+		t.Fatalf("Expected an error for an unknown ramp")
+	}
+}
+
+func TestHeatmap_Write(t *testing.T) {
+	h, err := New(testBounds, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error for New. error=%s", err)
+	}
+	h.Add([][2]float64{{0.01, 0.05}, {0.01, 0.95}})
+	h.Add([][2]float64{{0.01, 0.05}, {0.01, 0.45}})
+
+	var buf bytes.Buffer
+	if err := h.WritePng(&buf, Ramps["gray"]); err != nil {
+		t.Fatalf("Unexpected error for WritePng. error=%s", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error decoding PNG. error=%s", err)
+	}
+	if r, _, _, a := img.At(0, 4).RGBA(); r>>8 != 0xff || a>>8 != 0xff {
+		t.Fatalf("Most intense pixel was not white. actual=%v", img.At(0, 4))
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Fatalf("Empty pixel was not transparent. actual=%v", img.At(0, 0))
+	}
+
+	buf.Reset()
+	if err := h.WriteSvg(&buf, Ramps["gray"]); err != nil {
+		t.Fatalf("Unexpected error for WriteSvg. error=%s", err)
+	}
+	// One run for each intensity
+	if rects := strings.Count(buf.String(), "<rect"); rects != 2 {
+		t.Fatalf("Rect count was not as expected. expected=2, actual=%d", rects)
+	}
+}
+
+func closeBounds(a Bounds, b Bounds) bool {
+	const epsilon = 1e-9
+	return abs(a.MinLat-b.MinLat) < epsilon && abs(a.MinLng-b.MinLng) < epsilon &&
+		abs(a.MaxLat-b.MaxLat) < epsilon && abs(a.MaxLng-b.MaxLng) < epsilon
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,106 @@
+package heatmap
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Colors intensities are interpolated between, from least to most intense.
+type Ramp []color.RGBA
+
+// Named ramps for ParseRamp
+var Ramps = map[string]Ramp{
+	"hot":    {{0x40, 0, 0, 0xff}, {0xff, 0x20, 0, 0xff}, {0xff, 0xd0, 0, 0xff}, {0xff, 0xff, 0xff, 0xff}},
+	"blue":   {{0, 0x20, 0x60, 0xff}, {0, 0x90, 0xff, 0xff}, {0x90, 0xff, 0xff, 0xff}, {0xff, 0xff, 0xff, 0xff}},
+	"strava": {{0x80, 0x26, 0x01, 0xff}, {0xfc, 0x4c, 0x02, 0xff}, {0xff, 0xc0, 0x80, 0xff}},
+	"gray":   {{0x40, 0x40, 0x40, 0xff}, {0xff, 0xff, 0xff, 0xff}},
+}
+
+// Parse a ramp name, or comma separated colors as #rrggbb from least to most intense.
+func ParseRamp(s string) (Ramp, error) {
+	if ramp, found := Ramps[s]; found {
+		return ramp, nil
+	}
+
+	parts := strings.Split(s, ",")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("Unknown color ramp %s", s)
+	}
+
+	ramp := make(Ramp, len(parts))
+	for i, part := range parts {
+		part = strings.TrimPrefix(strings.TrimSpace(part), "#")
+		value, err := strconv.ParseUint(part, 16, 32)
+		if err != nil || len(part) != 6 {
+			return nil, fmt.Errorf("Invalid color %s; colors must be #rrggbb", parts[i])
+		}
+		ramp[i] = color.RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 0xff}
+	}
+	return ramp, nil
+}
+
+// The color of an intensity in [0, 1].
+func (r Ramp) At(intensity float64) color.RGBA {
+	if intensity <= 0 {
+		return r[0]
+	}
+	if intensity >= 1 {
+		return r[len(r)-1]
+	}
+
+	position := intensity * float64(len(r)-1)
+	i := int(position)
+	f := position - float64(i)
+	lerp := func(a uint8, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*f + 0.5)
+	}
+	return color.RGBA{lerp(r[i].R, r[i+1].R), lerp(r[i].G, r[i+1].G), lerp(r[i].B, r[i+1].B), lerp(r[i].A, r[i+1].A)}
+}
+
+// Render the heatmap with pixels no track passed through left transparent.
+func (h *Heatmap) Image(ramp Ramp) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, h.width, h.height))
+	max := h.max()
+	for y := 0; y < h.height; y++ {
+		for x := 0; x < h.width; x++ {
+			if count := h.Count(x, y); count > 0 {
+				img.SetRGBA(x, y, ramp.At(h.intensity(count, max)))
+			}
+		}
+	}
+	return img
+}
+
+func (h *Heatmap) WritePng(w io.Writer, ramp Ramp) error {
+	return png.Encode(w, h.Image(ramp))
+}
+
+// Write the heatmap as an SVG of one rectangle per run of same colored pixels in each row,
+// so it stays sharp when scaled.
+func (h *Heatmap) WriteSvg(w io.Writer, ramp Ramp) error {
+	img := h.Image(ramp)
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		h.width, h.height, h.width, h.height)
+	for y := 0; y < h.height; y++ {
+		for x := 0; x < h.width; {
+			c := img.RGBAAt(x, y)
+			run := 1
+			for x+run < h.width && img.RGBAAt(x+run, y) == c {
+				run++
+			}
+			if c.A > 0 {
+				fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="1" fill="#%02x%02x%02x"/>`+"\n", x, y, run, c.R, c.G, c.B)
+			}
+			x += run
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "</svg>\n")
+	return err
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/alecholmes/strava/bulkexport"
+	"github.com/alecholmes/strava/store"
+)
+
+// Loads the activities of a Strava bulk export archive into the local store, so they can be
+// used with --offline without fetching them from Strava.
+func importMain(flags *flag.FlagSet, args []string) error {
+	archiveFlag := flags.String("archive", "", "bulk export zip file downloaded from Strava; required")
+	storeFlag := flags.String("store", "", "directory of the local activity store; required")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *archiveFlag == "" || *storeFlag == "" {
+		return usageErrorf("--archive and --store are required")
+	}
+
+	activityStore, err := store.Open(*storeFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	result, err := bulkexport.ImportFile(*archiveFlag)
+	if err != nil {
+		return fmt.Errorf("reading archive: %s", err)
+	}
+
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s\n", skipped)
+	}
+
+	streams := 0
+	for _, activity := range result.Activities {
+		if err := importActivity(activityStore, activity); err != nil {
+			return fmt.Errorf("storing activity %d: %s", activity.Activity.Id, err)
+		}
+		if activity.Streams != nil {
+			streams++
+		}
+	}
+
+	fmt.Printf("Imported %d activities and %d streams\n", len(result.Activities), streams)
+	return nil
+}
+
+// Store an imported activity. Activities already synced from Strava are left as they are,
+// since they have details the export doesn't, but missing streams are filled in.
+func importActivity(activityStore *store.Store, activity *bulkexport.Activity) error {
+	if !activityStore.HasActivity(activity.Activity.Id) {
+		if err := activityStore.PutSummary(activity.Summary()); err != nil {
+			return err
+		}
+		if err := activityStore.PutActivity(activity.Activity); err != nil {
+			return err
+		}
+	}
+
+	if activity.Streams != nil && !activityStore.HasStreams(activity.Activity.Id) {
+		return activityStore.PutStreams(activity.Streams)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alecholmes/strava/analysis"
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/store"
+)
+
+// Prints daily training loads, or the stress of each activity, computed from power or heart rate streams.
+func loadReportMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	afterFlag := flags.Int("afterId", 0, "beginning activity id, exclusive")
+	ftpFlag := flags.Float64("ftp", 0, "functional threshold power in watts; defaults to the athlete's FTP on Strava")
+	thresholdHrFlag := flags.Float64("thresholdHr", 0, "lactate threshold heart rate, for activities without power")
+	throughFlag := flags.String("through", "", "last date to report loads for, as YYYY-MM-DD; defaults to today")
+	activitiesFlag := flags.Bool("activities", false, "print the stress of each activity rather than daily loads")
+	storeFlags := addStoreFlags(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	through := time.Now()
+	if *throughFlag != "" {
+		date, err := time.Parse(dateLayout, *throughFlag)
+		if err != nil {
+			return usageErrorf("invalid through date %s", *throughFlag)
+		}
+		through = date
+	}
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+	if *accessTokenFlag == "" && *ftpFlag == 0 && *thresholdHrFlag == 0 {
+		return usageErrorf("--ftp or --thresholdHr is required without --accessToken")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *storeFlags.cacheDir); err != nil {
+		return err
+	}
+
+	thresholds := analysis.Thresholds{Ftp: *ftpFlag, HeartRate: *thresholdHrFlag}
+	if thresholds.Ftp == 0 && *accessTokenFlag != "" {
+		ftp, err := athleteFtp(*accessTokenFlag, *storeFlags.cacheDir)
+		if err != nil {
+			return fmt.Errorf("getting athlete: %s", err)
+		}
+		thresholds.Ftp = ftp
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	summaries, err := source.GetActivitySummaries(model.ActivityId(*afterFlag))
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	stresses, err := activityStresses(source, summaries, thresholds)
+	if err != nil {
+		return err
+	}
+
+	if *activitiesFlag {
+		return outputFlags.print(stressTable(summaries, stresses))
+	}
+	return outputFlags.print(loadTable(analysis.TrainingLoad(stresses, through)))
+}
+
+func athleteFtp(accessToken string, cacheDir string) (float64, error) {
+	stravaClient, err := newClient(accessToken, cacheDir)
+	if err != nil {
+		return 0, err
+	}
+
+	athlete, err := stravaClient.GetAthlete()
+	if err != nil {
+		return 0, err
+	}
+	return float64(athlete.Ftp), nil
+}
+
+// The stress of each activity with usable streams. The number of activities without is
+// written to stderr, since they make loads look lower than they were.
+func activityStresses(source store.Source, summaries []*model.ActivitySummary, thresholds analysis.Thresholds) ([]*analysis.Stress, error) {
+	stresses := make([]*analysis.Stress, 0, len(summaries))
+	skipped := 0
+	for _, summary := range summaries {
+		streams, err := source.GetActivityStreams(summary.Id)
+		if err == store.ErrNotFound {
+			skipped++
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("getting streams of activity %d: %s", summary.Id, err)
+		}
+
+		if stress := analysis.ActivityStress(summary, streams, thresholds); stress != nil {
+			stresses = append(stresses, stress)
+		} else {
+			skipped++
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d activities without power or heart rate streams\n", skipped)
+	}
+	return stresses, nil
+}
+
+func stressTable(summaries []*model.ActivitySummary, stresses []*analysis.Stress) *table {
+	names := make(map[model.ActivityId]string)
+	for _, summary := range summaries {
+		names[summary.Id] = summary.Name
+	}
+
+	t := newTable("id", "name", "date", "source", "duration", "normalized_power", "intensity_factor", "tss")
+	for _, stress := range stresses {
+		source := "power"
+		if stress.HeartRate {
+			source = "heartrate"
+		}
+		t.add(stress.ActivityId, names[stress.ActivityId], stress.Date.Format(dateLayout), source, duration(stress.Duration),
+			stress.NormalizedPower, stress.IntensityFactor, stress.TSS)
+	}
+	return t
+}
+
+func loadTable(loads []*analysis.Load) *table {
+	t := newTable("date", "tss", "ctl", "atl", "tsb")
+	for _, load := range loads {
+		t.add(load.Date.Format(dateLayout), load.TSS, load.CTL, load.ATL, load.TSB)
+	}
+	return t
+}
@@ -1,102 +1,210 @@
 package main
 
 import (
-	"encoding/csv"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
-	"unicode/utf8"
+	"strings"
+)
 
-	"github.com/alecholmes/strava/client"
-	"github.com/alecholmes/strava/model"
+// Exit statuses
+const (
+	exitOk    = 0
+	exitError = 1
+	exitUsage = 2 // Like the flag package
 )
 
+// A CLI command, run as "strava <name> [flags] [args]".
+type command struct {
+	name        string // Words following "strava", such as "activities list"
+	args        string // Positional arguments for usage, if any, such as "<activity id>"
+	description string
+	run         func(flags *flag.FlagSet, args []string) error
+}
+
+// An error in how a command was run. The command's usage is printed after it.
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// All commands, in the order they are listed in help. Commands with a common first word,
+// like "report", form a group.
+var commands = []*command{
+	{name: "activities list", description: "List activity summaries, oldest first.", run: activitiesListMain},
+	{name: "activities get", args: "<activity id>", description: "Print the details of an activity.", run: activitiesGetMain},
+	{name: "segments efforts", description: "List the segment efforts of activities.", run: segmentEffortsMain},
+	{name: "segments leaderboard", args: "<segment id>", description: "Print a page of a segment's leaderboard.", run: segmentLeaderboardMain},
+	{name: "club", description: "List the athlete's clubs, or the members or recent activities of a club.", run: clubMain},
+	{name: "routes", description: "List the athlete's routes, or export them as GPX or TCX files.", run: routesMain},
+	{name: "photos", description: "Download the photos of activities.", run: photosMain},
+	{name: "compare", args: "<activity id> <activity id>", description: "Compare segment times, or time along the way, between two activities.", run: compareMain},
+	{name: "group-ride", args: "<activity id>", description: "Compare the athletes of a group ride, overall or on each segment.", run: groupRideMain},
+	{name: "heatmap", description: "Draw the tracks of all activities as a heatmap image.", run: heatmapMain},
+	{name: "sync", description: "Fetch new activities into a local store, so other commands can run with --offline.", run: syncMain},
+	{name: "import", description: "Load a Strava bulk export archive into a local store.", run: importMain},
+	{name: "export", description: "Write the tracks of activities to GPX files, or of activities or segments as GeoJSON or KML.", run: exportMain},
+	{name: "report gear", description: "Print the distance and time of activities per piece of gear.", run: gearReportMain},
+	{name: "report volume", description: "Print distance, time and elevation totals per week, month or year.", run: volumeReportMain},
+	{name: "report load", description: "Print daily fitness, fatigue and form, or the training stress of each activity.", run: loadReportMain},
+	{name: "report curve", description: "Print best average power or pace over standard durations, such as 5 seconds or 20 minutes.", run: curveReportMain},
+	{name: "report pr", description: "Print personal records on segments, reconstructed from all stored efforts.", run: prReportMain},
+	{name: "report social", description: "Print kudos and comment counts per activity, most kudoed first.", run: socialReportMain},
+	{name: "auth login", description: "Authorize this app with Strava in a browser and save the token for the profile.", run: authLoginMain},
+}
+
 func main() {
-	accessTokenFlag := flag.String("accessToken", "", "access token; required")
-	afterFlag := flag.Int("afterId", 0, "beginning activity id, exclusive")
-	segmentsFlag := flag.Bool("segments", false, "print segment details")
-	delimiterFlag := flag.String("delimiter", ",", "output field delimiter character")
-	flag.Parse()
-
-	if *accessTokenFlag == "" {
-		flag.Usage()
-		return
+	os.Exit(run(os.Args[1:]))
+}
+
+// Run the command named by args, returning the exit status.
+func run(args []string) int {
+	if len(args) == 0 {
+		printHelp(os.Stderr, "")
+		return exitUsage
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" || args[0] == "-help" {
+		if len(args) > 1 {
+			// Running a command with -h prints its usage
+			if cmd, _ := findCommand(args[1:]); cmd != nil {
+				return runCommand(cmd, []string{"-h"})
+			}
+			if len(groupCommands(args[1])) > 0 {
+				printHelp(os.Stdout, args[1])
+				return exitOk
+			}
+		}
+		printHelp(os.Stdout, "")
+		return exitOk
 	}
 
-	delimiter, size := utf8.DecodeRuneInString(*delimiterFlag)
-	if size == 0 || len(*delimiterFlag) != size {
-		fmt.Println("Delimiter can only be one character")
-		flag.Usage()
-		return
+	cmd, rest := findCommand(args)
+	if cmd == nil {
+		if group := groupCommands(args[0]); len(group) > 0 {
+			fmt.Fprintf(os.Stderr, "strava %s: a subcommand is required\n\n", args[0])
+			printHelp(os.Stderr, args[0])
+		} else {
+			fmt.Fprintf(os.Stderr, "strava: unknown command %s\n\n", args[0])
+			printHelp(os.Stderr, "")
+		}
+		return exitUsage
 	}
 
-	client := client.NewClient(*accessTokenFlag)
+	return runCommand(cmd, rest)
+}
+
+// The command named by the leading words of args, along with the remaining args.
+func findCommand(args []string) (*command, []string) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) < len(words) {
+			continue
+		}
 
-	activitySummaries, err := client.GetActivitySummaries(model.ActivityId(*afterFlag))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting activity summaries: %s", err)
+		found := true
+		for i, word := range words {
+			found = found && args[i] == word
+		}
+		if found {
+			return cmd, args[len(words):]
+		}
 	}
+	return nil, nil
+}
 
-	if *segmentsFlag {
-		activities, err := getActivities(client, activitySummaries)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting activities: %s", err)
+// Commands whose first word is group, if it names a group of commands.
+func groupCommands(group string) []*command {
+	grouped := make([]*command, 0)
+	for _, cmd := range commands {
+		if words := strings.Fields(cmd.name); len(words) > 1 && words[0] == group {
+			grouped = append(grouped, cmd)
 		}
-		printCsv(delimiter, segmentTuples(activities))
-	} else {
-		printCsv(delimiter, summaryTuples(activitySummaries))
 	}
+	return grouped
 }
 
-func getActivities(client client.Client, summaries []*model.ActivitySummary) ([]*model.Activity, error) {
-	activityIds := make([]model.ActivityId, len(summaries))
-	for i, summary := range summaries {
-		activityIds[i] = summary.Id
+func runCommand(cmd *command, args []string) int {
+	flags := newCommandFlags(cmd)
+	err := cmd.run(flags, args)
+	switch e := err.(type) {
+	case nil:
+		return exitOk
+	case *usageError:
+		fmt.Fprintf(os.Stderr, "strava %s: %s\n\n", cmd.name, e.message)
+		printUsage(os.Stderr, cmd, flags)
+		return exitUsage
+	}
+
+	if err == flag.ErrHelp {
+		printUsage(os.Stdout, cmd, flags)
+		return exitOk
 	}
+	fmt.Fprintf(os.Stderr, "strava %s: %s\n", cmd.name, err)
+	return exitError
+}
 
-	return client.GetActivities(activityIds)
+// The flag set a command defines its flags on, with --profile already defined.
+func newCommandFlags(cmd *command) *flag.FlagSet {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	// Parse errors are reported by runCommand, along with usage
+	flags.SetOutput(ioutil.Discard)
+	addProfileFlag(flags)
+	return flags
 }
 
-func printCsv(delimiter rune, tuples [][]string) {
-	writer := csv.NewWriter(os.Stdout)
-	writer.Comma = delimiter
-	writer.WriteAll(tuples)
+// Parse a command's flags, then fill in those not given from the selected profile.
+// Parse errors are usage errors.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err == flag.ErrHelp {
+		return err
+	} else if err != nil {
+		return &usageError{message: err.Error()}
+	}
+	return applyProfile(flags)
 }
 
-func summaryTuples(summaries []*model.ActivitySummary) [][]string {
-	tuples := make([][]string, len(summaries))
-	for i, summary := range summaries {
-		tuple := make([]string, 8)
-		tuple[0] = fmt.Sprintf("%d", summary.Id)
-		tuple[1] = summary.Name
-		tuple[2] = fmt.Sprintf("%.2f", summary.Distance)
-		tuple[3] = fmt.Sprintf("%.2f", summary.AverageSpeed)
-		tuple[4] = fmt.Sprintf("%.2f", summary.TotalElevationGain)
-		tuple[5] = fmt.Sprintf("%d", summary.MovingTime)
-		tuple[6] = fmt.Sprintf("%d", summary.ElapsedTime)
-		tuple[7] = summary.StartDate.String()
-		tuples[i] = tuple
+func printUsage(w io.Writer, cmd *command, flags *flag.FlagSet) {
+	usage := "strava " + cmd.name + " [flags]"
+	if cmd.args != "" {
+		usage += " " + cmd.args
+	}
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n", usage, cmd.description)
+
+	hasFlags := false
+	flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintf(w, "\nFlags:\n")
+		flags.SetOutput(w)
+		flags.PrintDefaults()
+		flags.SetOutput(ioutil.Discard)
 	}
-	return tuples
 }
 
-func segmentTuples(activities []*model.Activity) [][]string {
-	tuples := make([][]string, 0)
-	for _, activity := range activities {
-		for _, effort := range activity.SegmentEfforts {
-			tuple := make([]string, 10)
-			tuple[0] = fmt.Sprintf("%d", activity.Id)
-			tuple[1] = activity.Name
-			tuple[2] = fmt.Sprintf("%d", effort.Id)
-			tuple[3] = fmt.Sprintf("%d", effort.Segment.Id)
-			tuple[4] = effort.Segment.Name
-			tuple[5] = fmt.Sprintf("%.2f", effort.Segment.Distance)
-			tuple[6] = fmt.Sprintf("%d", effort.Segment.ClimbCategory)
-			tuple[7] = fmt.Sprintf("%d", effort.ElapsedTime)
-			tuple[8] = fmt.Sprintf("%d", effort.PrRank)
-			tuple[9] = effort.StartDate.String()
-			tuples = append(tuples, tuple)
+// Print the list of commands, or only those of a group if it is set.
+func printHelp(w io.Writer, group string) {
+	fmt.Fprintf(w, "Usage: strava <command> [flags]\n\nCommands:\n")
+
+	width := 0
+	for _, cmd := range commands {
+		if len(cmd.name) > width {
+			width = len(cmd.name)
+		}
+	}
+	for _, cmd := range commands {
+		if group == "" || strings.HasPrefix(cmd.name, group+" ") {
+			fmt.Fprintf(w, "  %-*s  %s\n", width, cmd.name, cmd.description)
 		}
 	}
-	return tuples
+
+	fmt.Fprintf(w, "\nRun \"strava help <command>\" for a command's flags.\n")
 }
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+// Defining a flag twice panics, so define every command's flags and ask for help, which
+// returns before the command does anything.
+func TestCommandFlags(t *testing.T) {
+	for _, cmd := range commands {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("Command %s panicked defining its flags. error=%v", cmd.name, r)
+				}
+			}()
+
+			if err := cmd.run(newCommandFlags(cmd), []string{"-h"}); err != flag.ErrHelp {
+				t.Fatalf("Command %s did not return flag.ErrHelp for -h. error=%v", cmd.name, err)
+			}
+		}()
+	}
+}
@@ -9,6 +9,7 @@ type ActivityId uint64
 type Activity struct {
 	Id                 ActivityId       `json:"id"`
 	Name               string           `json:"name"`
+	Type               string           `json:"type"` // Ride, Run, Swim, etc.
 	Athlete            *Athlete         `json:"athlete"`
 	StartDate          time.Time        `json:"start_date"`
 	StartDateLocal     time.Time        `json:"start_date_local"`
@@ -19,5 +20,11 @@ type Activity struct {
 	TotalElevationGain float32          `json:"total_elevation_gain"` // Meters
 	AverageSpeed       float32          `json:"average_speed"`        // Meters/sec
 	MaxSpeed           float32          `json:"max_speed"`            // Meters/sec
+	GearId             GearId           `json:"gear_id"`
+	KudosCount         uint32           `json:"kudos_count"`
+	CommentCount       uint32           `json:"comment_count"`
+	TotalPhotoCount    uint32           `json:"total_photo_count"`
+	Map                *Map             `json:"map"`
+	Laps               []*Lap           `json:"laps"`
 	SegmentEfforts     []*SegmentEffort `json:"segment_efforts"`
 }
@@ -7,6 +7,7 @@ import (
 type ActivitySummary struct {
 	Id                 ActivityId `json:"id"`
 	Name               string     `json:"name"`
+	Type               string     `json:"type"` // Ride, Run, Swim, etc.
 	Athlete            *Athlete   `json:"athlete"`
 	StartDate          time.Time  `json:"start_date"`
 	StartDateLocal     time.Time  `json:"start_date_local"`
@@ -17,4 +18,9 @@ type ActivitySummary struct {
 	TotalElevationGain float32    `json:"total_elevation_gain"` // Meters
 	AverageSpeed       float32    `json:"average_speed"`        // Meters/sec
 	MaxSpeed           float32    `json:"max_speed"`            // Meters/sec
+	GearId             GearId     `json:"gear_id"`
+	KudosCount         uint32     `json:"kudos_count"`
+	CommentCount       uint32     `json:"comment_count"`
+	TotalPhotoCount    uint32     `json:"total_photo_count"`
+	Map                *Map       `json:"map"`
 }
@@ -11,9 +11,11 @@ const (
 )
 
 type Athlete struct {
-	Id        AthleteId         `json:"id"`
-	FirstName string            `json:"firstname"`
-	LastName  string            `json:"lastname"`
-	Friend    RelationshipState `json:"friend"`
-	Follower  RelationshipState `json:"follower"`
+	Id                    AthleteId         `json:"id"`
+	FirstName             string            `json:"firstname"`
+	LastName              string            `json:"lastname"`
+	Friend                RelationshipState `json:"friend"`
+	Follower              RelationshipState `json:"follower"`
+	MeasurementPreference string            `json:"measurement_preference"` // feet or meters
+	Ftp                   uint32            `json:"ftp"`                    // Functional threshold power in watts, if set
 }
@@ -0,0 +1,16 @@
+package model
+
+type ClubId int64
+
+type Club struct {
+	Id          ClubId `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ClubType    string `json:"club_type"`  // casual_club, racing_team, shop, company or other
+	SportType   string `json:"sport_type"` // cycling, running, triathlon or other
+	City        string `json:"city"`
+	State       string `json:"state"`
+	Country     string `json:"country"`
+	Private     bool   `json:"private"`
+	MemberCount uint32 `json:"member_count"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+type CommentId int64
+
+type Comment struct {
+	Id         CommentId  `json:"id"`
+	ActivityId ActivityId `json:"activity_id"`
+	Text       string     `json:"text"`
+	Athlete    *Athlete   `json:"athlete"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
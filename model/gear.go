@@ -0,0 +1,14 @@
+package model
+
+type GearId string
+
+type Gear struct {
+	Id          GearId  `json:"id"`
+	Name        string  `json:"name"`
+	BrandName   string  `json:"brand_name"`
+	ModelName   string  `json:"model_name"`
+	Description string  `json:"description"`
+	Distance    float32 `json:"distance"` // Meters, over the gear's lifetime
+	Primary     bool    `json:"primary"`
+	Retired     bool    `json:"retired"`
+}
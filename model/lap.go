@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+type LapId int64
+
+// A lap of an activity, either recorded by the device or split automatically.
+type Lap struct {
+	Id                 LapId     `json:"id"`
+	Name               string    `json:"name"`
+	LapIndex           uint32    `json:"lap_index"` // Starting at 1
+	StartDate          time.Time `json:"start_date"`
+	StartDateLocal     time.Time `json:"start_date_local"`
+	ElapsedTime        uint32    `json:"elapsed_time"`         // Seconds
+	MovingTime         uint32    `json:"moving_time"`          // Seconds
+	StartIndex         uint32    `json:"start_index"`          // Index into the activity's streams
+	EndIndex           uint32    `json:"end_index"`            // Index into the activity's streams, inclusive
+	Distance           float32   `json:"distance"`             // Meters
+	TotalElevationGain float32   `json:"total_elevation_gain"` // Meters
+	AverageSpeed       float32   `json:"average_speed"`        // Meters/sec
+	MaxSpeed           float32   `json:"max_speed"`            // Meters/sec
+	AverageCadence     float32   `json:"average_cadence"`
+	AverageWatts       float32   `json:"average_watts"`
+	AverageHeartrate   float32   `json:"average_heartrate"`
+	MaxHeartrate       float32   `json:"max_heartrate"`
+}
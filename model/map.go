@@ -0,0 +1,9 @@
+package model
+
+// An encoded polyline of a route or activity.
+// https://developers.google.com/maps/documentation/utilities/polylinealgorithm
+type Map struct {
+	Id              string `json:"id"`
+	Polyline        string `json:"polyline"`
+	SummaryPolyline string `json:"summary_polyline"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+type Photo struct {
+	UniqueId   string            `json:"unique_id"`
+	ActivityId ActivityId        `json:"activity_id"`
+	Urls       map[string]string `json:"urls"` // Keyed by size in pixels
+	Caption    string            `json:"caption"`
+	Location   []float64         `json:"location"` // [latitude, longitude], if known
+	Source     uint8             `json:"source"`   // 1 for Strava, 2 for Instagram
+	CreatedAt  time.Time         `json:"created_at"`
+}
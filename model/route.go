@@ -0,0 +1,24 @@
+package model
+
+type RouteId int64
+type RouteType uint8
+
+const (
+	RideRoute = RouteType(1)
+	RunRoute  = RouteType(2)
+)
+
+type Route struct {
+	Id            RouteId    `json:"id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	Athlete       *Athlete   `json:"athlete"`
+	Type          RouteType  `json:"type"`
+	Distance      float32    `json:"distance"`       // Meters
+	ElevationGain float32    `json:"elevation_gain"` // Meters
+	Private       bool       `json:"private"`
+	Starred       bool       `json:"starred"`
+	Timestamp     int64      `json:"timestamp"` // Seconds since epoch
+	Map           *Map       `json:"map"`
+	Segments      []*Segment `json:"segments"`
+}
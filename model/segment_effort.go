@@ -9,6 +9,8 @@ type SegmentEffortId int64
 type SegmentEffort struct {
 	Id             SegmentEffortId `json:"id"`
 	ElapsedTime    uint32          `json:"elapsed_time"`
+	StartIndex     uint32          `json:"start_index"` // Index into the activity's streams
+	EndIndex       uint32          `json:"end_index"`   // Index into the activity's streams, inclusive
 	StartDate      time.Time       `json:"start_date"`
 	StartDateLocal time.Time       `json:"start_date_local"`
 	PrRank         uint32          `json:"pr_rank"`
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+type SegmentLeaderboard struct {
+	EffortCount uint32                     `json:"effort_count"`
+	EntryCount  uint32                     `json:"entry_count"`
+	Entries     []*SegmentLeaderboardEntry `json:"entries"`
+}
+
+type SegmentLeaderboardEntry struct {
+	Rank           uint32          `json:"rank"`
+	AthleteId      AthleteId       `json:"athlete_id"`
+	AthleteName    string          `json:"athlete_name"`
+	AthleteGender  string          `json:"athlete_gender"`
+	ActivityId     ActivityId      `json:"activity_id"`
+	EffortId       SegmentEffortId `json:"effort_id"`
+	ElapsedTime    uint32          `json:"elapsed_time"` // Seconds
+	MovingTime     uint32          `json:"moving_time"`  // Seconds
+	Distance       float32         `json:"distance"`     // Meters
+	AverageHr      float32         `json:"average_hr"`   // Beats/min
+	AverageWatts   float32         `json:"average_watts"`
+	StartDate      time.Time       `json:"start_date"`
+	StartDateLocal time.Time       `json:"start_date_local"`
+}
@@ -0,0 +1,24 @@
+package model
+
+// Data recorded over the course of an activity. Each stream has one value per sample,
+// so values at the same index were recorded at the same time. Streams that were not
+// recorded are empty.
+type Streams struct {
+	ActivityId     ActivityId   `json:"activity_id"`
+	Time           []uint32     `json:"time"`            // Seconds since start
+	LatLng         [][2]float64 `json:"latlng"`          // [latitude, longitude]
+	Distance       []float32    `json:"distance"`        // Meters since start
+	Altitude       []float32    `json:"altitude"`        // Meters
+	VelocitySmooth []float32    `json:"velocity_smooth"` // Meters/sec
+	Heartrate      []float32    `json:"heartrate"`       // Beats/min
+	Cadence        []float32    `json:"cadence"`         // Revolutions/min
+	Watts          []float32    `json:"watts"`
+	Temp           []float32    `json:"temp"` // Degrees Celsius
+	Moving         []bool       `json:"moving"`
+	GradeSmooth    []float32    `json:"grade_smooth"` // Percent
+}
+
+// Number of samples in the streams.
+func (s *Streams) Len() int {
+	return len(s.Time)
+}
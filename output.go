@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alecholmes/strava/units"
+)
+
+// Output formats for --format
+const (
+	csvFormat      = "csv"
+	tsvFormat      = "tsv"
+	jsonFormat     = "json"
+	ndjsonFormat   = "ndjson"
+	tableFormat    = "table"
+	markdownFormat = "markdown"
+)
+
+var formats = []string{csvFormat, tsvFormat, jsonFormat, ndjsonFormat, tableFormat, markdownFormat}
+
+// Values for --units besides units.Metric and units.Imperial
+const (
+	rawUnits  = "raw"
+	autoUnits = "auto"
+)
+
+// Table values with units, which are converted to the system selected by --units.
+// Raw values are printed as they came from Strava.
+type distance float64  // Meters
+type elevation float64 // Meters
+type speed float64     // Meters/sec
+type pace float64      // Meters/sec, shown as time per kilometer or mile
+type duration uint64   // Seconds
+
+// The speed of an activity, or its pace if that is how the activity type is usually measured.
+func speedOrPace(activityType string, metersPerSecond float32) interface{} {
+	if units.UsesPace(activityType) {
+		return pace(metersPerSecond)
+	}
+	return speed(metersPerSecond)
+}
+
+// Rows of command output with named columns. Values keep their types, so JSON output has
+// numbers and times, and are formatted as text for the other formats.
+type table struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+func newTable(columns ...string) *table {
+	return &table{columns: columns, rows: make([][]interface{}, 0)}
+}
+
+func (t *table) add(values ...interface{}) {
+	t.rows = append(t.rows, values)
+}
+
+// A table with only the named columns, in the given order.
+func (t *table) selectColumns(names []string) (*table, error) {
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		indexes[i] = -1
+		for j, column := range t.columns {
+			if column == name {
+				indexes[i] = j
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("Unknown field %s; fields are %s", name, strings.Join(t.columns, ","))
+		}
+	}
+
+	selected := newTable(names...)
+	for _, row := range t.rows {
+		values := make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			values[i] = row[index]
+		}
+		selected.add(values...)
+	}
+	return selected, nil
+}
+
+// Flags for commands that print a table.
+type outputFlags struct {
+	format    *string
+	fields    *string
+	header    *bool
+	delimiter *string
+	units     *string
+	system    units.System // Empty for raw values
+}
+
+func addOutputFlags(flags *flag.FlagSet) *outputFlags {
+	return &outputFlags{
+		format:    flags.String("format", csvFormat, "output format: "+strings.Join(formats, ", ")),
+		fields:    flags.String("fields", "", "comma separated fields to print, in order; all fields if not set"),
+		header:    flags.Bool("header", false, "print a header row of field names in csv and tsv output"),
+		delimiter: flags.String("delimiter", ",", "output field delimiter character for csv output"),
+		units:     flags.String("units", rawUnits, "units to print measurements in: raw, metric, imperial, or auto for the athlete's preference"),
+	}
+}
+
+// Check the flags, so commands can fail before doing any work.
+func (f *outputFlags) validate() error {
+	if _, ok := parseDelimiter(*f.delimiter); !ok {
+		return fmt.Errorf("Delimiter can only be one character")
+	}
+	if *f.units != rawUnits && *f.units != autoUnits {
+		system, err := units.ParseSystem(*f.units)
+		if err != nil {
+			return fmt.Errorf("Units must be raw, metric, imperial or auto")
+		}
+		f.system = system
+	}
+	for _, format := range formats {
+		if *f.format == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("Format must be one of %s", strings.Join(formats, ", "))
+}
+
+// Validate the flags and resolve --units auto. Invalid flags are usage errors.
+func (f *outputFlags) setup(accessToken string, cacheDir string) error {
+	if err := f.validate(); err != nil {
+		return &usageError{message: err.Error()}
+	}
+	if err := f.resolveUnits(accessToken, cacheDir); err != nil {
+		return fmt.Errorf("getting measurement preference: %s", err)
+	}
+	return nil
+}
+
+// Get the athlete's preferred units from Strava if --units is auto.
+func (f *outputFlags) resolveUnits(accessToken string, cacheDir string) error {
+	if *f.units != autoUnits {
+		return nil
+	}
+	if accessToken == "" {
+		return fmt.Errorf("--units auto requires an access token to get the athlete's measurement preference")
+	}
+
+	stravaClient, err := newClient(accessToken, cacheDir)
+	if err != nil {
+		return err
+	}
+	athlete, err := stravaClient.GetAthlete()
+	if err != nil {
+		return err
+	}
+
+	f.system = units.ForAthlete(athlete)
+	return nil
+}
+
+// Print a table to stdout in the selected format.
+func (f *outputFlags) print(t *table) error {
+	return f.write(os.Stdout, t)
+}
+
+func (f *outputFlags) write(w io.Writer, t *table) error {
+	if err := f.validate(); err != nil {
+		return err
+	}
+
+	t = convertUnits(t, f.system, *f.format == jsonFormat || *f.format == ndjsonFormat)
+
+	if *f.fields != "" {
+		selected, err := t.selectColumns(strings.Split(*f.fields, ","))
+		if err != nil {
+			return err
+		}
+		t = selected
+	}
+
+	switch *f.format {
+	case tsvFormat:
+		return writeDelimited(w, '\t', *f.header, t)
+	case jsonFormat:
+		return writeJson(w, t)
+	case ndjsonFormat:
+		return writeNdjson(w, t)
+	case tableFormat:
+		return writeAligned(w, t)
+	case markdownFormat:
+		return writeMarkdown(w, t)
+	}
+	delimiter, _ := parseDelimiter(*f.delimiter)
+	return writeDelimited(w, delimiter, *f.header, t)
+}
+
+// Convert values with units to the given system. JSON values stay numbers: distance, elevation
+// and speed are converted, pace becomes seconds per kilometer or mile, and durations stay
+// in seconds. Values are formatted with their units for text output.
+func convertUnits(t *table, system units.System, numeric bool) *table {
+	if system == "" {
+		return t
+	}
+
+	converted := newTable(t.columns...)
+	for _, row := range t.rows {
+		values := make([]interface{}, len(row))
+		for i, value := range row {
+			values[i] = convertValue(value, system, numeric)
+		}
+		converted.add(values...)
+	}
+	return converted
+}
+
+func convertValue(value interface{}, system units.System, numeric bool) interface{} {
+	switch v := value.(type) {
+	case distance:
+		if numeric {
+			return round(system.Distance(float64(v)))
+		}
+		return system.FormatDistance(float64(v))
+	case elevation:
+		if numeric {
+			return round(system.Elevation(float64(v)))
+		}
+		return system.FormatElevation(float64(v))
+	case speed:
+		if numeric {
+			return round(system.Speed(float64(v)))
+		}
+		return system.FormatSpeed(float64(v))
+	case pace:
+		if numeric {
+			return round(system.Pace(float64(v)))
+		}
+		return system.FormatPace(float64(v))
+	case duration:
+		if numeric {
+			return uint64(v)
+		}
+		return units.FormatDuration(uint64(v))
+	}
+	return value
+}
+
+// Round to 3 decimal places, so converted JSON values aren't overly precise.
+func round(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
+
+// Format a value as text. Floats are rounded to 2 places.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case float32, float64, distance, elevation, speed, pace:
+		return fmt.Sprintf("%.2f", v)
+	case time.Time:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+func formatRow(row []interface{}) []string {
+	values := make([]string, len(row))
+	for i, value := range row {
+		values[i] = formatValue(value)
+	}
+	return values
+}
+
+// Parse a single character delimiter. Returns false if the string is not exactly one character.
+func parseDelimiter(delimiterStr string) (rune, bool) {
+	delimiter, size := utf8.DecodeRuneInString(delimiterStr)
+	return delimiter, size != 0 && len(delimiterStr) == size
+}
+
+func writeDelimited(w io.Writer, delimiter rune, header bool, t *table) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	if header {
+		writer.Write(t.columns)
+	}
+	for _, row := range t.rows {
+		writer.Write(formatRow(row))
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// JSON of a row as an object with keys in column order.
+func rowJson(columns []string, row []interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(row[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func writeJson(w io.Writer, t *table) error {
+	objects := make([]json.RawMessage, len(t.rows))
+	for i, row := range t.rows {
+		object, err := rowJson(t.columns, row)
+		if err != nil {
+			return err
+		}
+		objects[i] = object
+	}
+
+	body, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", body)
+	return err
+}
+
+func writeNdjson(w io.Writer, t *table) error {
+	for _, row := range t.rows {
+		object, err := rowJson(t.columns, row)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeAligned(w io.Writer, t *table) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(t.columns, "\t"))
+	for _, row := range t.rows {
+		values := formatRow(row)
+		for i, value := range values {
+			// Tabs and newlines would break the alignment
+			values[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(value)
+		}
+		fmt.Fprintln(writer, strings.Join(values, "\t"))
+	}
+	return writer.Flush()
+}
+
+func writeMarkdown(w io.Writer, t *table) error {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+
+	separators := make([]string, len(t.columns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	lines := []string{
+		"| " + strings.Join(t.columns, " | ") + " |",
+		"| " + strings.Join(separators, " | ") + " |",
+	}
+	for _, row := range t.rows {
+		values := formatRow(row)
+		for i, value := range values {
+			values[i] = escape.Replace(value)
+		}
+		lines = append(lines, "| "+strings.Join(values, " | ")+" |")
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
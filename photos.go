@@ -0,0 +1,192 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/alecholmes/strava/client"
+	"github.com/alecholmes/strava/model"
+)
+
+const dateLayout = "2006-01-02"
+
+// Downloads all photos of activities in a date range into
+// dir/<year>/<date>_<activity id>/<photo id>.<ext>. Photos already downloaded are skipped.
+func photosMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	dirFlag := flags.String("dir", "", "directory to download photos into; required")
+	fromFlag := flags.String("from", "", "first activity date to include, as YYYY-MM-DD")
+	toFlag := flags.String("to", "", "last activity date to include, as YYYY-MM-DD")
+	sizeFlag := flags.Int("size", 2048, "requested photo size in pixels")
+	cacheFlag := addCacheFlag(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *accessTokenFlag == "" || *dirFlag == "" {
+		return usageErrorf("--accessToken and --dir are required")
+	}
+
+	from, to, err := parseDateRange(*fromFlag, *toFlag)
+	if err != nil {
+		return &usageError{message: err.Error()}
+	}
+
+	stravaClient, err := newClient(*accessTokenFlag, *cacheFlag)
+	if err != nil {
+		return fmt.Errorf("creating client: %s", err)
+	}
+
+	summaries, err := stravaClient.GetActivitySummaries(client.Beginning)
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	failures := 0
+	for _, summary := range summaries {
+		if summary.TotalPhotoCount == 0 || summary.StartDateLocal.Before(from) || !summary.StartDateLocal.Before(to) {
+			continue
+		}
+
+		photos, err := stravaClient.ListActivityPhotos(summary.Id, *sizeFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting photos for activity %d: %s\n", summary.Id, err)
+			failures++
+			continue
+		}
+
+		activityDir := filepath.Join(*dirFlag, summary.StartDateLocal.Format("2006"),
+			fmt.Sprintf("%s_%d", summary.StartDateLocal.Format(dateLayout), summary.Id))
+		for _, photo := range photos {
+			downloaded, err := downloadPhoto(photo, *sizeFlag, activityDir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error downloading photo %s: %s\n", photo.UniqueId, err)
+				failures++
+			} else if downloaded != "" {
+				fmt.Println(downloaded)
+			}
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d downloads failed", failures)
+	}
+	return nil
+}
+
+// Parse an inclusive date range. Either end may be empty, meaning unbounded.
+// Returns [from, to) where to is the start of the day after the last included date.
+func parseDateRange(fromStr string, toStr string) (time.Time, time.Time, error) {
+	from := time.Time{}
+	to := time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if fromStr != "" {
+		date, err := time.Parse(dateLayout, fromStr)
+		if err != nil {
+			return from, to, fmt.Errorf("Invalid from date %s", fromStr)
+		}
+		from = date
+	}
+	if toStr != "" {
+		date, err := time.Parse(dateLayout, toStr)
+		if err != nil {
+			return from, to, fmt.Errorf("Invalid to date %s", toStr)
+		}
+		to = date.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("From date %s is after to date %s", fromStr, toStr)
+	}
+
+	return from, to, nil
+}
+
+// Download a photo into dir, returning the path written to.
+// Returns an empty path if the photo was already downloaded or has no url.
+func downloadPhoto(photo *model.Photo, size int, dir string) (string, error) {
+	photoUrl := photoUrl(photo, size)
+	if photoUrl == "" {
+		return "", nil
+	}
+
+	photoPath := filepath.Join(dir, photo.UniqueId+photoExt(photoUrl))
+	if _, err := os.Stat(photoPath); err == nil {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	response, err := http.Get(photoUrl)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 {
+		return "", fmt.Errorf("Unexpected HTTP response %v", response.Status)
+	}
+
+	// Write to a temporary file first so an interrupted download is not mistaken for a complete one
+	tempFile, err := ioutil.TempFile(dir, ".download-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := io.Copy(tempFile, response.Body); err != nil {
+		tempFile.Close()
+		return "", err
+	}
+	if err := tempFile.Close(); err != nil {
+		return "", err
+	}
+
+	return photoPath, os.Rename(tempFile.Name(), photoPath)
+}
+
+// The file extension of a photo url, ignoring any query string such as a CDN signature.
+// Defaults to .jpg if the path has no plausible extension.
+func photoExt(photoUrl string) string {
+	u, err := url.Parse(photoUrl)
+	if err != nil {
+		return ".jpg"
+	}
+
+	ext := path.Ext(u.Path)
+	if ext == "" || len(ext) > 5 {
+		return ".jpg"
+	}
+	return ext
+}
+
+// The url for the given size, or for the largest available size if that is missing.
+func photoUrl(photo *model.Photo, size int) string {
+	if photoUrl, found := photo.Urls[strconv.Itoa(size)]; found {
+		return photoUrl
+	}
+
+	sizes := make([]int, 0, len(photo.Urls))
+	for sizeStr := range photo.Urls {
+		if size, err := strconv.Atoi(sizeStr); err == nil {
+			sizes = append(sizes, size)
+		}
+	}
+	if len(sizes) == 0 {
+		return ""
+	}
+
+	sort.Ints(sizes)
+	return photo.Urls[strconv.Itoa(sizes[len(sizes)-1])]
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestParseDateRange(t *testing.T) {
+	unbounded := time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		from         string
+		to           string
+		expectedFrom time.Time
+		expectedTo   time.Time
+		err          bool
+	}{
+		{"", "", time.Time{}, unbounded, false},
+		{"2015-06-01", "", time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC), unbounded, false},
+		{"", "2015-06-30", time.Time{}, time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2015-06-01", "2015-06-01", time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2015, 6, 2, 0, 0, 0, 0, time.UTC), false},
+		{"2015-06-02", "2015-06-01", time.Time{}, time.Time{}, true},
+		{"06/01/2015", "", time.Time{}, time.Time{}, true},
+		{"", "2015-13-01", time.Time{}, time.Time{}, true},
+	}
+
+	for _, c := range cases {
+		from, to, err := parseDateRange(c.from, c.to)
+		if c.err {
+			if err == nil {
+				t.Fatalf("Expected error for range %q to %q", c.from, c.to)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error for range %q to %q. error=%s", c.from, c.to, err)
+		}
+		if !from.Equal(c.expectedFrom) || !to.Equal(c.expectedTo) {
+			t.Fatalf("Range %q to %q was not as expected. expected=[%v, %v), actual=[%v, %v)",
+				c.from, c.to, c.expectedFrom, c.expectedTo, from, to)
+		}
+	}
+}
+
+func TestPhotoUrl(t *testing.T) {
+	cases := []struct {
+		urls     map[string]string
+		size     int
+		expected string
+	}{
+		{map[string]string{"100": "small.jpg", "2048": "large.jpg"}, 2048, "large.jpg"},
+		{map[string]string{"100": "small.jpg", "2048": "large.jpg"}, 100, "small.jpg"},
+		// Missing sizes fall back to the largest available, compared numerically
+		{map[string]string{"100": "small.jpg", "600": "medium.jpg"}, 2048, "medium.jpg"},
+		{map[string]string{"1000": "large.jpg", "600": "medium.jpg"}, 2048, "large.jpg"},
+		{map[string]string{"0": "placeholder.jpg", "original": "original.jpg"}, 2048, "placeholder.jpg"},
+		{map[string]string{"original": "original.jpg"}, 2048, ""},
+		{nil, 2048, ""},
+	}
+
+	for _, c := range cases {
+		if actual := photoUrl(&model.Photo{Urls: c.urls}, c.size); actual != c.expected {
+			t.Fatalf("Url of %v at size %d was not as expected. expected=%s, actual=%s", c.urls, c.size, c.expected, actual)
+		}
+	}
+}
+
+func TestPhotoExt(t *testing.T) {
+	cases := map[string]string{
+		"https://dgtzuqphqg23d.cloudfront.net/abc-2048x1536.png":             ".png",
+		"https://dgtzuqphqg23d.cloudfront.net/abc-2048x1536.jpg?sig=a.b&e=1": ".jpg",
+		"https://example.com/photos/abc.jpeg#fragment":                       ".jpeg",
+		"https://example.com/photos/abc?format=.png":                         ".jpg",
+		"https://example.com/photos/abc.unknownext":                          ".jpg",
+		"%zz": ".jpg",
+	}
+
+	for photoUrl, expected := range cases {
+		if actual := photoExt(photoUrl); actual != expected {
+			t.Fatalf("Extension of %s was not as expected. expected=%s, actual=%s", photoUrl, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package polyline
+
+import (
+	"errors"
+)
+
+var ErrInvalid = errors.New("invalid encoded polyline")
+
+// Decode a polyline in Google's encoded polyline format, as used by model.Map, into
+// [latitude, longitude] points.
+// https://developers.google.com/maps/documentation/utilities/polylinealgorithm
+func Decode(encoded string) ([][2]float64, error) {
+	points := make([][2]float64, 0, len(encoded)/4)
+
+	var lat, lng int64
+	for i := 0; i < len(encoded); {
+		dLat, next, err := decodeValue(encoded, i)
+		if err != nil {
+			return nil, err
+		}
+		dLng, next, err := decodeValue(encoded, next)
+		if err != nil {
+			return nil, err
+		}
+		i = next
+
+		lat += dLat
+		lng += dLng
+		points = append(points, [2]float64{float64(lat) / 1e5, float64(lng) / 1e5})
+	}
+
+	return points, nil
+}
+
+// Encode [latitude, longitude] points as a polyline, rounding to 5 decimal places.
+func Encode(points [][2]float64) string {
+	encoded := make([]byte, 0, len(points)*8)
+
+	var prevLat, prevLng int64
+	for _, point := range points {
+		lat, lng := round(point[0]*1e5), round(point[1]*1e5)
+		encoded = encodeValue(encoded, lat-prevLat)
+		encoded = encodeValue(encoded, lng-prevLng)
+		prevLat, prevLng = lat, lng
+	}
+
+	return string(encoded)
+}
+
+// Decode the value starting at index i, returning it and the index following it.
+// Values are chunks of 5 bits, least significant first, each offset by 63 and with 0x20
+// set if another chunk follows. The lowest bit of the result is the sign.
+func decodeValue(encoded string, i int) (int64, int, error) {
+	var result int64
+	for shift := uint(0); ; shift += 5 {
+		if i >= len(encoded) || shift > 60 {
+			return 0, 0, ErrInvalid
+		}
+
+		b := int64(encoded[i]) - 63
+		i++
+		if b < 0 || b > 63 {
+			return 0, 0, ErrInvalid
+		}
+
+		result |= (b & 0x1f) << shift
+		if b < 0x20 {
+			break
+		}
+	}
+
+	if result&1 != 0 {
+		return ^(result >> 1), i, nil
+	}
+	return result >> 1, i, nil
+}
+
+func encodeValue(encoded []byte, value int64) []byte {
+	v := value << 1
+	if value < 0 {
+		v = ^v
+	}
+
+	for v >= 0x20 {
+		encoded = append(encoded, byte((v&0x1f)|0x20)+63)
+		v >>= 5
+	}
+	return append(encoded, byte(v)+63)
+}
+
+func round(value float64) int64 {
+	if value < 0 {
+		return int64(value - 0.5)
+	}
+	return int64(value + 0.5)
+}
@@ -0,0 +1,36 @@
+package polyline
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The example from Google's documentation
+const exampleEncoded = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+
+var examplePoints = [][2]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+
+func TestDecode(t *testing.T) {
+	points, err := Decode(exampleEncoded)
+	if err != nil {
+		t.Fatalf("Unexpected error for Decode. error=%s", err)
+	}
+	if !reflect.DeepEqual(points, examplePoints) {
+		t.Fatalf("Points were not as expected. expected=%v, actual=%v", examplePoints, points)
+	}
+
+	if points, err := Decode(""); err != nil || len(points) != 0 {
+		t.Fatalf("Expected no points for an empty polyline. points=%v, error=%v", points, err)
+	}
+
+	// Truncated in the middle of a longitude
+	if _, err := Decode(exampleEncoded[:8]); err != ErrInvalid {
+		t.Fatalf("Expected ErrInvalid but got %v", err)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	if encoded := Encode(examplePoints); encoded != exampleEncoded {
+		t.Fatalf("Encoded polyline was not as expected. expected=%s, actual=%s", exampleEncoded, encoded)
+	}
+}
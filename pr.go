@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/alecholmes/strava/analysis"
+	"github.com/alecholmes/strava/store"
+)
+
+// Prints personal records reconstructed from the segment efforts in the local store: the
+// record and gap to it of each segment, every record ever set, recent records, or segments
+// not attempted for a while.
+func prReportMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addUnitsAccessTokenFlag(flags)
+	storeFlag := flags.String("store", "", "directory of the local activity store; required")
+	progressionFlag := flags.Bool("progression", false, "print every record set on each segment")
+	recentFlag := flags.Int("recentDays", 0, "only print records set in this many days")
+	staleFlag := flags.Int("staleMonths", 0, "only print segments not attempted in this many months")
+	cacheFlag := addCacheFlag(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *storeFlag == "" {
+		return usageErrorf("--store is required")
+	}
+	if *recentFlag < 0 || *staleFlag < 0 || (*recentFlag > 0 && *staleFlag > 0) {
+		return usageErrorf("only one of --recentDays and --staleMonths may be given, as a positive number")
+	}
+	// Efforts are only read from the store, so the access token is only used for --units auto
+	if err := outputFlags.setup(string(*accessTokenFlag), *cacheFlag); err != nil {
+		return err
+	}
+
+	activityStore, err := store.Open(*storeFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	efforts, err := activityStore.Efforts()
+	if err != nil {
+		return fmt.Errorf("getting efforts: %s", err)
+	}
+	records := analysis.SegmentRecords(efforts)
+
+	now := time.Now()
+	switch {
+	case *recentFlag > 0:
+		return outputFlags.print(prTable(analysis.RecentPrs(records, now.AddDate(0, 0, -*recentFlag))))
+	case *staleFlag > 0:
+		return outputFlags.print(segmentRecordTable(analysis.StaleSegments(records, now.AddDate(0, -*staleFlag, 0))))
+	case *progressionFlag:
+		return outputFlags.print(prTable(analysis.Progression(records)))
+	}
+	return outputFlags.print(segmentRecordTable(records))
+}
+
+func segmentRecordTable(records []*analysis.SegmentRecord) *table {
+	t := newTable("segment_id", "segment_name", "attempts", "pr_time", "pr_date", "pr_activity_id", "latest_time",
+		"latest_date", "gap", "gap_percent")
+	for _, record := range records {
+		best, latest := record.Best(), record.Latest()
+		gapPercent := 0.0
+		if best.Effort.ElapsedTime > 0 {
+			gapPercent = float64(record.Gap()) / float64(best.Effort.ElapsedTime) * 100
+		}
+		t.add(record.Segment.Id, record.Segment.Name, len(record.Efforts), duration(best.Effort.ElapsedTime),
+			best.Effort.StartDateLocal.Format(dateLayout), best.ActivityId, duration(latest.Effort.ElapsedTime),
+			latest.Effort.StartDateLocal.Format(dateLayout), duration(record.Gap()), gapPercent)
+	}
+	return t
+}
+
+func prTable(prs []*analysis.Pr) *table {
+	t := newTable("segment_id", "segment_name", "effort_id", "activity_id", "date", "elapsed_time", "improvement")
+	for _, pr := range prs {
+		t.add(pr.Segment.Id, pr.Segment.Name, pr.Effort.Effort.Id, pr.Effort.ActivityId,
+			pr.Effort.Effort.StartDateLocal.Format(dateLayout), duration(pr.Effort.Effort.ElapsedTime), duration(pr.Improvement))
+	}
+	return t
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/alecholmes/strava/auth"
+	"github.com/alecholmes/strava/config"
+)
+
+func addProfileFlag(flags *flag.FlagSet) *string {
+	return flags.String("profile", config.DefaultProfile, "profile in the config file and credential store to use")
+}
+
+// The profile selected with --profile.
+func profileName(flags *flag.FlagSet) string {
+	if f := flags.Lookup("profile"); f != nil {
+		return f.Value.String()
+	}
+	return config.DefaultProfile
+}
+
+// Set flags that weren't given on the command line from the selected profile: defaults from
+// the config file, and --accessToken from the credential store. The credential store is only
+// read when the token is needed, so a token given with --accessToken or a command running
+// --offline is never refreshed.
+func applyProfile(flags *flag.FlagSet) error {
+	dir, err := config.Dir()
+	if err != nil {
+		// Without a home directory there is nowhere for profiles to be
+		return nil
+	}
+	conf, err := config.Load(dir)
+	if err != nil {
+		return fmt.Errorf("reading config: %s", err)
+	}
+
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+	name := profileName(flags)
+	profile := conf.Profile(name)
+	defaults := map[string]string{
+		"format":    profile.Format,
+		"delimiter": profile.Delimiter,
+		"units":     profile.Units,
+		"store":     profile.Store,
+		"cache":     profile.Cache,
+	}
+	for flagName, value := range defaults {
+		if value == "" || given[flagName] || flags.Lookup(flagName) == nil {
+			continue
+		}
+		if err := flags.Set(flagName, value); err != nil {
+			return fmt.Errorf("profile %s has an invalid %s: %s", name, flagName, err)
+		}
+	}
+
+	readsStrava := flags.Lookup("accessToken") != nil
+	offline := flags.Lookup("offline") != nil && flags.Lookup("offline").Value.String() == "true"
+	needsToken := readsStrava && !given["accessToken"] && !offline
+	if f := flags.Lookup("accessToken"); f != nil {
+		if _, unitsOnly := f.Value.(*unitsAccessToken); unitsOnly && flags.Lookup("units").Value.String() != autoUnits {
+			needsToken = false
+		}
+	}
+
+	// Commands that log in create profiles, so only those reading Strava require one to exist.
+	// A profile that isn't configured may still exist in the credential store.
+	_, configured := conf.Profiles[name]
+	checkExists := readsStrava && name != config.DefaultProfile && !configured
+	if !needsToken && !checkExists {
+		return nil
+	}
+
+	credentialStore, err := config.OpenCredentials(dir)
+	if err != nil {
+		return fmt.Errorf("reading credentials: %s", err)
+	}
+	credentials := credentialStore.Get(name)
+	if credentials != nil && credentials.Token == nil {
+		// A hand-edited entry may be missing its token
+		credentials = nil
+	}
+	if checkExists && credentials == nil {
+		return usageErrorf("unknown profile %s", name)
+	}
+	if !needsToken || credentials == nil {
+		return nil
+	}
+
+	if credentials.Token.Expired(time.Now()) {
+		token, err := auth.NewAuthenticator(credentials.ClientId, credentials.ClientSecret).Refresh(credentials.Token.RefreshToken)
+		if err != nil {
+			return fmt.Errorf("refreshing the access token of profile %s: %s", name, err)
+		}
+		// Only exchanging a code returns the athlete
+		token.Athlete = credentials.Token.Athlete
+		credentials.Token = token
+		if err := credentialStore.Put(name, credentials); err != nil {
+			return fmt.Errorf("saving credentials: %s", err)
+		}
+	}
+
+	return flags.Set("accessToken", credentials.Token.AccessToken)
+}
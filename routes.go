@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/alecholmes/strava/client"
+	"github.com/alecholmes/strava/model"
+)
+
+func routesMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	dirFlag := flags.String("dir", "", "directory to export routes into; routes are listed if not set")
+	formatFlag := flags.String("exportFormat", "gpx", "export file format: gpx or tcx")
+	routeFlag := flags.Int64("routeId", 0, "only export the route with this id")
+	cacheFlag := addCacheFlag(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *accessTokenFlag == "" {
+		return usageErrorf("--accessToken is required")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *cacheFlag); err != nil {
+		return err
+	}
+
+	format := client.RouteFormat(*formatFlag)
+	if format != client.GPX && format != client.TCX {
+		return usageErrorf("--exportFormat must be gpx or tcx")
+	}
+	if *routeFlag != 0 && *dirFlag == "" {
+		return usageErrorf("--dir is required to export a route")
+	}
+
+	stravaClient, err := newClient(*accessTokenFlag, *cacheFlag)
+	if err != nil {
+		return fmt.Errorf("creating client: %s", err)
+	}
+
+	var routeIds []model.RouteId
+	if *routeFlag != 0 {
+		routeIds = []model.RouteId{model.RouteId(*routeFlag)}
+	} else {
+		athlete, err := stravaClient.GetAthlete()
+		if err != nil {
+			return fmt.Errorf("getting athlete: %s", err)
+		}
+
+		routes, err := stravaClient.ListAthleteRoutes(athlete.Id).All()
+		if err != nil {
+			return fmt.Errorf("getting routes: %s", err)
+		}
+
+		if *dirFlag == "" {
+			return outputFlags.print(routeTable(routes))
+		}
+
+		for _, route := range routes {
+			routeIds = append(routeIds, route.Id)
+		}
+	}
+
+	if err := exportRoutes(stravaClient, routeIds, format, *dirFlag); err != nil {
+		return fmt.Errorf("exporting routes: %s", err)
+	}
+	return nil
+}
+
+// Write each route to dir/<route id>.<format>, creating dir if needed.
+func exportRoutes(stravaClient client.Client, routeIds []model.RouteId, format client.RouteFormat, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, routeId := range routeIds {
+		body, err := stravaClient.ExportRoute(routeId, format)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("%d.%s", routeId, format))
+		if err := ioutil.WriteFile(path, body, 0644); err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+
+	return nil
+}
+
+func routeTable(routes []*model.Route) *table {
+	t := newTable("id", "name", "type", "distance", "elevation_gain")
+	for _, route := range routes {
+		t.add(route.Id, route.Name, route.Type, distance(route.Distance), elevation(route.ElevationGain))
+	}
+	return t
+}
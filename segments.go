@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/alecholmes/strava/client"
+	"github.com/alecholmes/strava/model"
+)
+
+func segmentEffortsMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	afterFlag := flags.Int("afterId", 0, "beginning activity id, exclusive")
+	storeFlags := addStoreFlags(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *storeFlags.cacheDir); err != nil {
+		return err
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	summaries, err := source.GetActivitySummaries(model.ActivityId(*afterFlag))
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	activities, err := getActivities(source, summaries)
+	if err != nil {
+		return fmt.Errorf("getting activities: %s", err)
+	}
+
+	return outputFlags.print(segmentTable(activities))
+}
+
+func segmentLeaderboardMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	pageFlag := flags.Int("page", 1, "leaderboard page to print")
+	genderFlag := flags.String("gender", "", "gender filter: M or F")
+	ageGroupFlag := flags.String("ageGroup", "", "age group filter, e.g. 25_34 or 65_plus")
+	weightClassFlag := flags.String("weightClass", "", "weight class filter, e.g. 150_164 (lb) or 75_84 (kg)")
+	followingFlag := flags.Bool("following", false, "only include followed athletes")
+	clubFlag := flags.Int64("clubId", 0, "only include members of this club")
+	dateRangeFlag := flags.String("dateRange", "", "date range: this_year, this_month, this_week or today")
+	cacheFlag := addCacheFlag(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return usageErrorf("a segment id is required")
+	}
+	segmentId, err := strconv.ParseInt(flags.Arg(0), 10, 64)
+	if err != nil {
+		return usageErrorf("invalid segment id %s", flags.Arg(0))
+	}
+	if *accessTokenFlag == "" {
+		return usageErrorf("--accessToken is required")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *cacheFlag); err != nil {
+		return err
+	}
+
+	stravaClient, err := newClient(*accessTokenFlag, *cacheFlag)
+	if err != nil {
+		return fmt.Errorf("creating client: %s", err)
+	}
+
+	filter := client.LeaderboardFilter{
+		Gender:      client.Gender(*genderFlag),
+		AgeGroup:    client.AgeGroup(*ageGroupFlag),
+		WeightClass: client.WeightClass(*weightClassFlag),
+		Following:   *followingFlag,
+		ClubId:      model.ClubId(*clubFlag),
+		DateRange:   client.DateRange(*dateRangeFlag),
+	}
+	leaderboard, err := stravaClient.GetSegmentLeaderboard(model.SegmentId(segmentId), &filter, *pageFlag)
+	if err != nil {
+		return fmt.Errorf("getting segment leaderboard: %s", err)
+	}
+
+	return outputFlags.print(leaderboardTable(leaderboard))
+}
+
+func segmentTable(activities []*model.Activity) *table {
+	t := newTable("activity_id", "activity_name", "effort_id", "segment_id", "segment_name", "distance",
+		"climb_category", "elapsed_time", "pr_rank", "start_date")
+	for _, activity := range activities {
+		for _, effort := range activity.SegmentEfforts {
+			t.add(activity.Id, activity.Name, effort.Id, effort.Segment.Id, effort.Segment.Name, distance(effort.Segment.Distance),
+				effort.Segment.ClimbCategory, duration(effort.ElapsedTime), effort.PrRank, effort.StartDate)
+		}
+	}
+	return t
+}
+
+func leaderboardTable(leaderboard *model.SegmentLeaderboard) *table {
+	t := newTable("rank", "athlete_id", "athlete_name", "elapsed_time", "moving_time", "activity_id", "start_date")
+	for _, entry := range leaderboard.Entries {
+		t.add(entry.Rank, entry.AthleteId, entry.AthleteName, duration(entry.ElapsedTime), duration(entry.MovingTime),
+			entry.ActivityId, entry.StartDate)
+	}
+	return t
+}
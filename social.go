@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alecholmes/strava/client"
+	"github.com/alecholmes/strava/model"
+)
+
+func socialReportMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	afterFlag := flags.Int("afterId", 0, "beginning activity id, exclusive")
+	kudoersFlag := flags.Bool("kudoers", false, "include the names of athletes who gave kudos")
+	storeFlags := addStoreFlags(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	// Kudoers are not stored, so listing them always requires Strava
+	if *accessTokenFlag == "" && (storeFlags.needsAccessToken() || *kudoersFlag) {
+		return usageErrorf("--accessToken is required unless --offline, and for --kudoers")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *storeFlags.cacheDir); err != nil {
+		return err
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	summaries, err := source.GetActivitySummaries(model.ActivityId(*afterFlag))
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	sorted := make([]*model.ActivitySummary, len(summaries))
+	copy(sorted, summaries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].KudosCount > sorted[j].KudosCount })
+
+	t := socialTable(sorted)
+	if *kudoersFlag {
+		t.columns = append(t.columns, "kudoers")
+		stravaClient, err := newClient(*accessTokenFlag, *storeFlags.cacheDir)
+		if err != nil {
+			return fmt.Errorf("creating client: %s", err)
+		}
+		for i, summary := range sorted {
+			names, err := kudoerNames(stravaClient, summary)
+			if err != nil {
+				return fmt.Errorf("getting kudoers: %s", err)
+			}
+			t.rows[i] = append(t.rows[i], strings.Join(names, ";"))
+		}
+	}
+
+	return outputFlags.print(t)
+}
+
+func kudoerNames(stravaClient client.Client, summary *model.ActivitySummary) ([]string, error) {
+	if summary.KudosCount == 0 {
+		return nil, nil
+	}
+
+	kudoers, err := stravaClient.ListActivityKudoers(summary.Id).All()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(kudoers))
+	for i, kudoer := range kudoers {
+		names[i] = strings.TrimSpace(kudoer.FirstName + " " + kudoer.LastName)
+	}
+	return names, nil
+}
+
+func socialTable(summaries []*model.ActivitySummary) *table {
+	t := newTable("id", "name", "kudos_count", "comment_count", "start_date")
+	for _, summary := range summaries {
+		t.add(summary.Id, summary.Name, summary.KudosCount, summary.CommentCount, summary.StartDate)
+	}
+	return t
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/alecholmes/strava/client"
+	"github.com/alecholmes/strava/store"
+)
+
+// How long cached responses are used without asking Strava. Gear rarely changes;
+// everything else is revalidated with a conditional request.
+var cacheTTLs = client.CacheTTLs{"/gear/": 24 * time.Hour}
+
+func addAccessTokenFlag(flags *flag.FlagSet) *string {
+	return flags.String("accessToken", "", "Strava access token; defaults to the token saved for the profile by auth login")
+}
+
+// An access token a command only uses for --units auto, such as one reading only the store.
+// The credential store is only read for it when --units is auto.
+type unitsAccessToken string
+
+func (t *unitsAccessToken) String() string {
+	if t == nil {
+		return ""
+	}
+	return string(*t)
+}
+
+func (t *unitsAccessToken) Set(value string) error {
+	*t = unitsAccessToken(value)
+	return nil
+}
+
+func addUnitsAccessTokenFlag(flags *flag.FlagSet) *unitsAccessToken {
+	token := new(unitsAccessToken)
+	flags.Var(token, "accessToken", "Strava access token, only used for --units auto; defaults to the token saved for the profile by auth login")
+	return token
+}
+
+func addCacheFlag(flags *flag.FlagSet) *string {
+	return flags.String("cache", "", "directory to cache Strava responses in, so unchanged responses aren't downloaded again")
+}
+
+// Create a client, caching responses in cacheDir if it is set.
+func newClient(accessToken string, cacheDir string) (client.Client, error) {
+	if cacheDir == "" {
+		return client.NewClient(accessToken), nil
+	}
+
+	cache, err := client.NewDiskCache(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewCachingClient(accessToken, cache, cacheTTLs), nil
+}
+
+// Flags for commands that can read activities from a local store instead of Strava.
+type storeFlags struct {
+	dir      *string
+	offline  *bool
+	cacheDir *string
+}
+
+func addStoreFlags(flags *flag.FlagSet) *storeFlags {
+	return &storeFlags{
+		dir:      flags.String("store", "", "directory of the local activity store, filled by the sync command"),
+		offline:  flags.Bool("offline", false, "read activities from the local store instead of Strava"),
+		cacheDir: addCacheFlag(flags),
+	}
+}
+
+// Whether an access token is needed, which is only when reading from Strava.
+func (f *storeFlags) needsAccessToken() bool {
+	return !*f.offline
+}
+
+// The source to read activities from: the local store if offline, otherwise Strava.
+func (f *storeFlags) source(accessToken string) (store.Source, error) {
+	if !*f.offline {
+		return newClient(accessToken, *f.cacheDir)
+	}
+
+	if *f.dir == "" {
+		return nil, errors.New("--offline requires --store")
+	}
+	return store.Open(*f.dir)
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// A change to one field of an activity summary, with values formatted for display.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// An activity whose summary changed on Strava.
+type ActivityChange struct {
+	Old    *model.ActivitySummary
+	New    *model.ActivitySummary
+	Fields []*FieldChange
+}
+
+// Differences between the stored and remote activity summaries, each ordered by activity id.
+type Diff struct {
+	Since   time.Time // When the store was last synced
+	Added   []*model.ActivitySummary
+	Changed []*ActivityChange
+	Deleted []*model.ActivitySummary
+}
+
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Deleted) == 0
+}
+
+// Compare all remote activity summaries with stored ones by id, catching the renames, type changes
+// and deletions of older activities that an incremental Sync misses. Changed and added activities
+// are stored and their details refetched; deleted activities are tombstoned.
+func (s *Store) Reconcile(source Source, options SyncOptions) (*Diff, error) {
+	since, err := s.LastSync()
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := source.GetActivitySummaries(beginning)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := s.GetActivitySummaries(beginning)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := compareSummaries(local, remote)
+	diff.Since = since
+
+	for _, summary := range diff.Added {
+		if err := s.PutSummary(summary); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, change := range diff.Changed {
+		if err := s.PutSummary(change.New); err != nil {
+			return nil, err
+		}
+
+		// Removing the stale details and streams makes them be refetched below
+		if err := s.deleteEfforts(change.New.Id); err != nil {
+			return nil, err
+		}
+		for _, bucket := range []string{activitiesBucket, streamsBucket} {
+			if err := s.delete(bucket, fmt.Sprintf("%d", change.New.Id)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, summary := range diff.Deleted {
+		if err := s.Tombstone(summary.Id, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.fillMissing(source, options); err != nil {
+		return nil, err
+	}
+
+	if err := s.setLastSync(now); err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+// Compare summaries by id and key fields.
+func compareSummaries(local []*model.ActivitySummary, remote []*model.ActivitySummary) *Diff {
+	diff := &Diff{
+		Added:   make([]*model.ActivitySummary, 0),
+		Changed: make([]*ActivityChange, 0),
+		Deleted: make([]*model.ActivitySummary, 0),
+	}
+
+	localMap := make(map[model.ActivityId]*model.ActivitySummary, len(local))
+	for _, summary := range local {
+		localMap[summary.Id] = summary
+	}
+
+	remoteIds := make(map[model.ActivityId]bool, len(remote))
+	for _, summary := range remote {
+		remoteIds[summary.Id] = true
+
+		old, found := localMap[summary.Id]
+		if !found {
+			diff.Added = append(diff.Added, summary)
+		} else if fields := changedFields(old, summary); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, &ActivityChange{Old: old, New: summary, Fields: fields})
+		}
+	}
+
+	for _, summary := range local {
+		if !remoteIds[summary.Id] {
+			diff.Deleted = append(diff.Deleted, summary)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Id < diff.Added[j].Id })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].New.Id < diff.Changed[j].New.Id })
+	sort.Slice(diff.Deleted, func(i, j int) bool { return diff.Deleted[i].Id < diff.Deleted[j].Id })
+
+	return diff
+}
+
+// The key fields that differ between two versions of an activity summary.
+func changedFields(old *model.ActivitySummary, new *model.ActivitySummary) []*FieldChange {
+	fields := make([]*FieldChange, 0)
+	compare := func(field string, oldValue string, newValue string) {
+		if oldValue != newValue {
+			fields = append(fields, &FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	compare("name", old.Name, new.Name)
+	compare("type", old.Type, new.Type)
+	compare("start_date", old.StartDate.UTC().String(), new.StartDate.UTC().String())
+	compare("distance", fmt.Sprintf("%.2f", old.Distance), fmt.Sprintf("%.2f", new.Distance))
+	compare("moving_time", fmt.Sprintf("%d", old.MovingTime), fmt.Sprintf("%d", new.MovingTime))
+	compare("elapsed_time", fmt.Sprintf("%d", old.ElapsedTime), fmt.Sprintf("%d", new.ElapsedTime))
+	compare("total_elevation_gain", fmt.Sprintf("%.2f", old.TotalElevationGain), fmt.Sprintf("%.2f", new.TotalElevationGain))
+	compare("gear_id", string(old.GearId), string(new.GearId))
+
+	return fields
+}
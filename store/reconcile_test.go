@@ -0,0 +1,90 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestCompareSummaries(t *testing.T) {
+	local := []*model.ActivitySummary{
+		{Id: 11, Name: "Morning Ride", Type: "Ride", Distance: 1000},
+		{Id: 22, Name: "Lunch Ride", Type: "Ride"},
+		{Id: 33, Name: "Evening Ride", Type: "Ride"},
+	}
+	remote := []*model.ActivitySummary{
+		{Id: 44, Name: "Night Ride", Type: "Ride"},
+		{Id: 11, Name: "Hawk Hill repeats", Type: "Ride", Distance: 1000},
+		{Id: 33, Name: "Evening Ride", Type: "Ride"},
+	}
+
+	diff := compareSummaries(local, remote)
+
+	if len(diff.Added) != 1 || diff.Added[0].Id != 44 {
+		t.Fatalf("Expected 44 to be added but got %v", diff.Added)
+	}
+	if len(diff.Deleted) != 1 || diff.Deleted[0].Id != 22 {
+		t.Fatalf("Expected 22 to be deleted but got %v", diff.Deleted)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].New.Id != 11 {
+		t.Fatalf("Expected 11 to be changed but got %v", diff.Changed)
+	}
+
+	expected := FieldChange{Field: "name", Old: "Morning Ride", New: "Hawk Hill repeats"}
+	fields := diff.Changed[0].Fields
+	if len(fields) != 1 || *fields[0] != expected {
+		t.Fatalf("Field changes were not as expected. expected=%v, actual=%v", expected, fields)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	source := &testSource{
+		summaries: []*model.ActivitySummary{{Id: 11, Type: "Ride"}, {Id: 22, Type: "Ride"}},
+		activities: map[model.ActivityId]*model.Activity{
+			11: {Id: 11, SegmentEfforts: []*model.SegmentEffort{{Id: 5, Segment: &model.Segment{Id: 7}}}},
+			22: {Id: 22, SegmentEfforts: []*model.SegmentEffort{{Id: 6, Segment: &model.Segment{Id: 7}}}},
+		},
+	}
+	if _, err := store.Sync(source, SyncOptions{}); err != nil {
+		t.Fatalf("Unexpected error for Sync. error=%s", err)
+	}
+
+	// 11 becomes a run with a different effort, and 22 is deleted
+	source.summaries = []*model.ActivitySummary{{Id: 11, Type: "Run"}}
+	source.activities[11] = &model.Activity{Id: 11, Type: "Run", SegmentEfforts: []*model.SegmentEffort{{Id: 8, Segment: &model.Segment{Id: 9}}}}
+
+	diff, err := store.Reconcile(source, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error for Reconcile. error=%s", err)
+	}
+	if len(diff.Added) != 0 || len(diff.Changed) != 1 || len(diff.Deleted) != 1 || diff.Since.IsZero() {
+		t.Fatalf("Diff was not as expected. diff=%v", diff)
+	}
+
+	activity, err := store.Activity(11)
+	if err != nil || activity.Type != "Run" {
+		t.Fatalf("Expected activity 11 to be refetched. activity=%v, error=%s", activity, err)
+	}
+
+	if _, err := store.Summary(22); err != ErrNotFound {
+		t.Fatalf("Expected summary 22 to be removed but got error %s", err)
+	}
+	tombstones, err := store.Tombstones()
+	if err != nil || len(tombstones) != 1 || tombstones[0].Summary.Id != 22 {
+		t.Fatalf("Expected tombstone for 22. tombstones=%v, error=%s", tombstones, err)
+	}
+
+	efforts, err := store.Efforts()
+	if err != nil || len(efforts) != 1 || efforts[0].Effort.Id != 8 {
+		t.Fatalf("Expected only the refetched effort to be stored. efforts=%v, error=%s", efforts, err)
+	}
+
+	// Nothing changed, so another reconcile should be empty
+	diff, err = store.Reconcile(source, SyncOptions{})
+	if err != nil || !diff.Empty() {
+		t.Fatalf("Expected empty diff. diff=%v, error=%s", diff, err)
+	}
+}
@@ -0,0 +1,401 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// Returned when an object is not in the store
+var ErrNotFound = errors.New("not found in store")
+
+// Buckets are subdirectories holding one JSON file per object, named by the object's id
+const (
+	summariesBucket  = "summaries"
+	activitiesBucket = "activities"
+	streamsBucket    = "streams"
+	effortsBucket    = "efforts"
+	gearBucket       = "gear"
+	tombstonesBucket = "tombstones"
+)
+
+var buckets = []string{summariesBucket, activitiesBucket, streamsBucket, effortsBucket, gearBucket, tombstonesBucket}
+
+// Name of the file recording when the store was last synced
+const stateFile = "state.json"
+
+// Records an activity that was deleted from Strava, along with its last stored summary.
+type Tombstone struct {
+	Summary   *model.ActivitySummary `json:"summary"`
+	DeletedAt time.Time              `json:"deleted_at"` // When the deletion was detected
+}
+
+type state struct {
+	LastSync time.Time `json:"last_sync"`
+}
+
+// A segment effort along with the activity it is part of.
+type Effort struct {
+	ActivityId model.ActivityId     `json:"activity_id"`
+	Effort     *model.SegmentEffort `json:"effort"`
+}
+
+// An on-disk store of activities and related data, kept as JSON files under a directory.
+// Writes are atomic, so an interrupted sync never leaves partially written objects.
+// A Store is not safe for concurrent writes to the same object.
+type Store struct {
+	dir string
+}
+
+// Open the store in dir, creating it if it does not exist.
+func Open(dir string) (*Store, error) {
+	for _, bucket := range buckets {
+		if err := os.MkdirAll(filepath.Join(dir, bucket), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) PutSummary(summary *model.ActivitySummary) error {
+	return s.put(summariesBucket, fmt.Sprintf("%d", summary.Id), summary)
+}
+
+func (s *Store) Summary(activityId model.ActivityId) (*model.ActivitySummary, error) {
+	var summary model.ActivitySummary
+	if err := s.get(summariesBucket, fmt.Sprintf("%d", activityId), &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Pass as after to get all activity summaries. The same as client.Beginning, which the store
+// doesn't import so it doesn't depend on the HTTP client.
+const beginning model.ActivityId = 0
+
+// Get stored activity summaries, oldest first, like client.Client.
+// Only activities with ids greater than the given after are included.
+func (s *Store) GetActivitySummaries(after model.ActivityId) ([]*model.ActivitySummary, error) {
+	ids, err := s.activityIds(summariesBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*model.ActivitySummary, 0, len(ids))
+	for _, id := range ids {
+		if id <= after {
+			continue
+		}
+
+		summary, err := s.Summary(id)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// The id of the newest stored activity summary, or 0 if there are none.
+func (s *Store) LatestActivityId() (model.ActivityId, error) {
+	ids, err := s.activityIds(summariesBucket)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[len(ids)-1], nil
+}
+
+// Store an activity's details. Its segment efforts are also stored individually,
+// replacing those of any previously stored version of the activity.
+func (s *Store) PutActivity(activity *model.Activity) error {
+	if err := s.deleteEfforts(activity.Id); err != nil {
+		return err
+	}
+
+	for _, effort := range activity.SegmentEfforts {
+		if err := s.put(effortsBucket, fmt.Sprintf("%d", effort.Id), &Effort{ActivityId: activity.Id, Effort: effort}); err != nil {
+			return err
+		}
+	}
+
+	return s.put(activitiesBucket, fmt.Sprintf("%d", activity.Id), activity)
+}
+
+func (s *Store) Activity(activityId model.ActivityId) (*model.Activity, error) {
+	var activity model.Activity
+	if err := s.get(activitiesBucket, fmt.Sprintf("%d", activityId), &activity); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// Whether the details of an activity are stored.
+func (s *Store) HasActivity(activityId model.ActivityId) bool {
+	_, err := os.Stat(s.path(activitiesBucket, fmt.Sprintf("%d", activityId)))
+	return err == nil
+}
+
+// Get multiple activities by their ids, returned in the same order, like client.Client.
+// Activities that are not stored are excluded.
+func (s *Store) GetActivities(activityIds []model.ActivityId) ([]*model.Activity, error) {
+	activities := make([]*model.Activity, 0, len(activityIds))
+	for _, activityId := range activityIds {
+		activity, err := s.Activity(activityId)
+		if err == ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		activities = append(activities, activity)
+	}
+	return activities, nil
+}
+
+func (s *Store) PutStreams(streams *model.Streams) error {
+	return s.put(streamsBucket, fmt.Sprintf("%d", streams.ActivityId), streams)
+}
+
+// Get the stored streams of an activity. Named like client.Client so either can be used.
+func (s *Store) GetActivityStreams(activityId model.ActivityId) (*model.Streams, error) {
+	var streams model.Streams
+	if err := s.get(streamsBucket, fmt.Sprintf("%d", activityId), &streams); err != nil {
+		return nil, err
+	}
+	return &streams, nil
+}
+
+// Whether the streams of an activity are stored.
+func (s *Store) HasStreams(activityId model.ActivityId) bool {
+	_, err := os.Stat(s.path(streamsBucket, fmt.Sprintf("%d", activityId)))
+	return err == nil
+}
+
+func (s *Store) Effort(effortId model.SegmentEffortId) (*Effort, error) {
+	var effort Effort
+	if err := s.get(effortsBucket, fmt.Sprintf("%d", effortId), &effort); err != nil {
+		return nil, err
+	}
+	return &effort, nil
+}
+
+// All stored segment efforts, oldest first.
+func (s *Store) Efforts() ([]*Effort, error) {
+	names, err := s.names(effortsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	efforts := make([]*Effort, 0, len(names))
+	for _, name := range names {
+		var effort Effort
+		if err := s.get(effortsBucket, name, &effort); err != nil {
+			return nil, err
+		}
+		efforts = append(efforts, &effort)
+	}
+
+	sort.SliceStable(efforts, func(i, j int) bool {
+		return efforts[i].Effort.StartDate.Before(efforts[j].Effort.StartDate)
+	})
+
+	return efforts, nil
+}
+
+// Remove an activity and everything stored for it, leaving a tombstone in its place.
+func (s *Store) Tombstone(activityId model.ActivityId, deletedAt time.Time) error {
+	summary, err := s.Summary(activityId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.put(tombstonesBucket, fmt.Sprintf("%d", activityId), &Tombstone{Summary: summary, DeletedAt: deletedAt}); err != nil {
+		return err
+	}
+
+	if err := s.deleteEfforts(activityId); err != nil {
+		return err
+	}
+	for _, bucket := range []string{streamsBucket, activitiesBucket, summariesBucket} {
+		if err := s.delete(bucket, fmt.Sprintf("%d", activityId)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Tombstones of all deleted activities, ordered by activity id.
+func (s *Store) Tombstones() ([]*Tombstone, error) {
+	ids, err := s.activityIds(tombstonesBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstones := make([]*Tombstone, len(ids))
+	for i, id := range ids {
+		var tombstone Tombstone
+		if err := s.get(tombstonesBucket, fmt.Sprintf("%d", id), &tombstone); err != nil {
+			return nil, err
+		}
+		tombstones[i] = &tombstone
+	}
+	return tombstones, nil
+}
+
+// When the store was last synced, or the zero time if it never was.
+func (s *Store) LastSync() (time.Time, error) {
+	var st state
+	body, err := ioutil.ReadFile(filepath.Join(s.dir, stateFile))
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	} else if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := json.Unmarshal(body, &st); err != nil {
+		return time.Time{}, err
+	}
+	return st.LastSync, nil
+}
+
+func (s *Store) setLastSync(lastSync time.Time) error {
+	body, err := json.Marshal(&state{LastSync: lastSync})
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(s.dir, stateFile), body)
+}
+
+func (s *Store) PutGear(gear *model.Gear) error {
+	return s.put(gearBucket, string(gear.Id), gear)
+}
+
+// Get stored gear. Named like client.Client so either can be used.
+func (s *Store) GetGear(gearId model.GearId) (*model.Gear, error) {
+	var gear model.Gear
+	if err := s.get(gearBucket, string(gearId), &gear); err != nil {
+		return nil, err
+	}
+	return &gear, nil
+}
+
+func (s *Store) path(bucket string, name string) string {
+	return filepath.Join(s.dir, bucket, name+".json")
+}
+
+// Write an object as JSON.
+func (s *Store) put(bucket string, name string, obj interface{}) error {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid store key %q", name)
+	}
+
+	body, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return writeFile(s.path(bucket, name), body)
+}
+
+// Write a file by writing a temporary file and renaming it over any existing file,
+// so a crash never leaves a truncated file.
+func writeFile(path string, body []byte) error {
+	tempFile, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(body); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), path)
+}
+
+func (s *Store) get(bucket string, name string, obj interface{}) error {
+	body, err := ioutil.ReadFile(s.path(bucket, name))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, obj)
+}
+
+func (s *Store) delete(bucket string, name string) error {
+	err := os.Remove(s.path(bucket, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Delete the stored efforts of the stored version of an activity, if any.
+func (s *Store) deleteEfforts(activityId model.ActivityId) error {
+	previous, err := s.Activity(activityId)
+	if err == ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, effort := range previous.SegmentEfforts {
+		if err := s.delete(effortsBucket, fmt.Sprintf("%d", effort.Id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Names of all objects in a bucket
+func (s *Store) names(bucket string) ([]string, error) {
+	files, err := ioutil.ReadDir(filepath.Join(s.dir, bucket))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(name, ".json"))
+	}
+	return names, nil
+}
+
+// Ids of all objects in a bucket keyed by activity id, in ascending order
+func (s *Store) activityIds(bucket string) ([]model.ActivityId, error) {
+	names, err := s.names(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]model.ActivityId, 0, len(names))
+	for _, name := range names {
+		var id uint64
+		if _, err := fmt.Sscanf(name, "%d", &id); err != nil {
+			return nil, fmt.Errorf("unexpected file %s in %s", name, bucket)
+		}
+		ids = append(ids, model.ActivityId(id))
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestStore_Summaries(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	for _, id := range []model.ActivityId{33, 11, 22} {
+		if err := store.PutSummary(&model.ActivitySummary{Id: id, Name: "Ride"}); err != nil {
+			t.Fatalf("Unexpected error for PutSummary. error=%s", err)
+		}
+	}
+
+	summaries, err := store.GetActivitySummaries(model.ActivityId(11))
+	if err != nil {
+		t.Fatalf("Unexpected error for GetActivitySummaries. error=%s", err)
+	}
+	if len(summaries) != 2 || summaries[0].Id != 22 || summaries[1].Id != 33 {
+		t.Fatalf("Expected summaries 22 and 33 but got %v", summaries)
+	}
+
+	latest, err := store.LatestActivityId()
+	if err != nil || latest != 33 {
+		t.Fatalf("Expected latest id 33. latest=%d, error=%s", latest, err)
+	}
+
+	if _, err := store.Summary(model.ActivityId(44)); err != ErrNotFound {
+		t.Fatalf("Expected ErrNotFound but got %s", err)
+	}
+}
+
+func TestStore_EmptyLatestActivityId(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	latest, err := store.LatestActivityId()
+	if err != nil || latest != 0 {
+		t.Fatalf("Expected latest id 0. latest=%d, error=%s", latest, err)
+	}
+}
+
+func TestStore_Activities(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	later := &model.SegmentEffort{Id: 2, StartDate: time.Date(2014, 10, 4, 16, 0, 0, 0, time.UTC), Segment: &model.Segment{Id: 7}}
+	earlier := &model.SegmentEffort{Id: 1, StartDate: time.Date(2014, 10, 4, 15, 0, 0, 0, time.UTC), Segment: &model.Segment{Id: 7}}
+	activity := &model.Activity{
+		Id:             123,
+		Name:           "Gran Fondo",
+		StartDate:      time.Date(2014, 10, 4, 15, 7, 31, 0, time.UTC),
+		SegmentEfforts: []*model.SegmentEffort{later, earlier},
+	}
+	if err := store.PutActivity(activity); err != nil {
+		t.Fatalf("Unexpected error for PutActivity. error=%s", err)
+	}
+
+	activities, err := store.GetActivities([]model.ActivityId{456, 123})
+	if err != nil {
+		t.Fatalf("Unexpected error for GetActivities. error=%s", err)
+	}
+	if len(activities) != 1 || !reflect.DeepEqual(activity, activities[0]) {
+		t.Fatalf("Activities were not the same. expected=%v, actual=%v", activity, activities)
+	}
+	if !store.HasActivity(123) || store.HasActivity(456) {
+		t.Fatalf("HasActivity was not as expected")
+	}
+
+	efforts, err := store.Efforts()
+	if err != nil {
+		t.Fatalf("Unexpected error for Efforts. error=%s", err)
+	}
+	if len(efforts) != 2 || efforts[0].Effort.Id != 1 || efforts[1].Effort.Id != 2 || efforts[0].ActivityId != 123 {
+		t.Fatalf("Efforts were not as expected. efforts=%v", efforts)
+	}
+}
+
+func TestStore_StreamsAndGear(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	streams := &model.Streams{ActivityId: 123, Time: []uint32{0, 1}, Watts: []float32{100, 200}}
+	if err := store.PutStreams(streams); err != nil {
+		t.Fatalf("Unexpected error for PutStreams. error=%s", err)
+	}
+	stored, err := store.GetActivityStreams(123)
+	if err != nil || !reflect.DeepEqual(streams, stored) {
+		t.Fatalf("Streams were not the same. expected=%v, actual=%v, error=%s", streams, stored, err)
+	}
+
+	gear := &model.Gear{Id: "b105763", Name: "Cannondale TT"}
+	if err := store.PutGear(gear); err != nil {
+		t.Fatalf("Unexpected error for PutGear. error=%s", err)
+	}
+	storedGear, err := store.GetGear("b105763")
+	if err != nil || !reflect.DeepEqual(gear, storedGear) {
+		t.Fatalf("Gear was not the same. expected=%v, actual=%v, error=%s", gear, storedGear, err)
+	}
+
+	if err := store.PutGear(&model.Gear{Id: "../escape"}); err == nil {
+		t.Fatalf("Expected error for invalid gear id")
+	}
+}
+
+func newTestStore(t *testing.T) (*Store, func()) {
+	dir, err := ioutil.TempDir("", "store-test")
+	if err != nil {
+		t.Fatalf("Could not create temp dir. error=%s", err)
+	}
+
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Could not open store. error=%s", err)
+	}
+
+	return store, func() { os.RemoveAll(dir) }
+}
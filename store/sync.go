@@ -0,0 +1,139 @@
+package store
+
+import (
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// Where activities are read from. Implemented by client.Client for Strava's API,
+// and by Store so that commands can run offline.
+type Source interface {
+	GetActivitySummaries(after model.ActivityId) ([]*model.ActivitySummary, error)
+	GetActivities(activityIds []model.ActivityId) ([]*model.Activity, error)
+	GetActivityStreams(activityId model.ActivityId) (*model.Streams, error)
+	GetGear(gearId model.GearId) (*model.Gear, error)
+}
+
+// Assert Store implements Source
+var _ Source = &Store{}
+
+type SyncOptions struct {
+	Streams bool // Also fetch streams, which are large and take one request per activity
+}
+
+// Counts of what a sync fetched.
+type SyncResult struct {
+	Summaries  int
+	Activities int
+	Streams    int
+	Gear       int
+
+	// Streams that failed to fetch, such as those of manual activities, which have none.
+	// They are retried by the next sync.
+	StreamsFailed int
+}
+
+// Fetch activities newer than the newest stored activity, using its id as after.
+// Details, and streams if requested, are fetched for any stored activity missing them,
+// so an interrupted sync picks up where it left off.
+func (s *Store) Sync(source Source, options SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{}
+
+	latest, err := s.LatestActivityId()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := source.GetActivitySummaries(latest)
+	if err != nil {
+		return nil, err
+	}
+
+	gearIds := make(map[model.GearId]bool)
+	for _, summary := range summaries {
+		if err := s.PutSummary(summary); err != nil {
+			return nil, err
+		}
+		if summary.GearId != "" {
+			gearIds[summary.GearId] = true
+		}
+		result.Summaries++
+	}
+
+	// Gear distances change with every activity, so gear used by new activities is refreshed
+	for gearId := range gearIds {
+		gear, err := source.GetGear(gearId)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.PutGear(gear); err != nil {
+			return nil, err
+		}
+		result.Gear++
+	}
+
+	fetched, err := s.fillMissing(source, options)
+	if err != nil {
+		return nil, err
+	}
+	result.Activities += fetched.Activities
+	result.Streams += fetched.Streams
+	result.StreamsFailed += fetched.StreamsFailed
+
+	if err := s.setLastSync(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Fetch details, and streams if requested, for all stored summaries missing them.
+func (s *Store) fillMissing(source Source, options SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{}
+
+	stored, err := s.activityIds(summariesBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make([]model.ActivityId, 0)
+	for _, activityId := range stored {
+		if !s.HasActivity(activityId) {
+			missing = append(missing, activityId)
+		}
+	}
+
+	// Activities that can't be fetched are left out and retried by the next sync
+	activities, err := source.GetActivities(missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, activity := range activities {
+		if err := s.PutActivity(activity); err != nil {
+			return nil, err
+		}
+		result.Activities++
+	}
+
+	if options.Streams {
+		for _, activityId := range stored {
+			if s.HasStreams(activityId) {
+				continue
+			}
+
+			// Like details, streams that can't be fetched are left out rather than failing the sync
+			streams, err := source.GetActivityStreams(activityId)
+			if err != nil {
+				result.StreamsFailed++
+				continue
+			}
+			if err := s.PutStreams(streams); err != nil {
+				return nil, err
+			}
+			result.Streams++
+		}
+	}
+
+	return result, nil
+}
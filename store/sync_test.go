@@ -0,0 +1,146 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+// A Source backed by maps, recording what was requested
+type testSource struct {
+	summaries        []*model.ActivitySummary
+	activities       map[model.ActivityId]*model.Activity
+	gear             map[model.GearId]*model.Gear
+	requestedAfter   []model.ActivityId
+	streamsRequested int
+	noStreams        map[model.ActivityId]bool // Activities whose streams fail to fetch
+}
+
+func (s *testSource) GetActivitySummaries(after model.ActivityId) ([]*model.ActivitySummary, error) {
+	s.requestedAfter = append(s.requestedAfter, after)
+	summaries := make([]*model.ActivitySummary, 0)
+	for _, summary := range s.summaries {
+		if summary.Id > after {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries, nil
+}
+
+func (s *testSource) GetActivities(activityIds []model.ActivityId) ([]*model.Activity, error) {
+	activities := make([]*model.Activity, 0)
+	for _, activityId := range activityIds {
+		if activity, found := s.activities[activityId]; found {
+			activities = append(activities, activity)
+		}
+	}
+	return activities, nil
+}
+
+func (s *testSource) GetActivityStreams(activityId model.ActivityId) (*model.Streams, error) {
+	s.streamsRequested++
+	if s.noStreams[activityId] {
+		return nil, errors.New("no streams")
+	}
+	return &model.Streams{ActivityId: activityId, Time: []uint32{0, 1}}, nil
+}
+
+func (s *testSource) GetGear(gearId model.GearId) (*model.Gear, error) {
+	gear, found := s.gear[gearId]
+	if !found {
+		return nil, errors.New("no such gear")
+	}
+	return gear, nil
+}
+
+func TestSync(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	source := &testSource{
+		summaries: []*model.ActivitySummary{{Id: 11, GearId: "b1"}, {Id: 22}},
+		activities: map[model.ActivityId]*model.Activity{
+			11: {Id: 11, SegmentEfforts: []*model.SegmentEffort{{Id: 5, Segment: &model.Segment{Id: 7}}}},
+		},
+		gear: map[model.GearId]*model.Gear{"b1": {Id: "b1", Name: "Bike"}},
+	}
+
+	result, err := store.Sync(source, SyncOptions{Streams: true})
+	if err != nil {
+		t.Fatalf("Unexpected error for Sync. error=%s", err)
+	}
+	expected := SyncResult{Summaries: 2, Activities: 1, Streams: 2, Gear: 1}
+	if *result != expected {
+		t.Fatalf("Sync result was not as expected. expected=%v, actual=%v", expected, *result)
+	}
+
+	// Activity 22 could not be fetched, so the next sync should retry it
+	source.summaries = append(source.summaries, &model.ActivitySummary{Id: 33})
+	source.activities[22] = &model.Activity{Id: 22}
+	source.activities[33] = &model.Activity{Id: 33}
+
+	result, err = store.Sync(source, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error for Sync. error=%s", err)
+	}
+	expected = SyncResult{Summaries: 1, Activities: 2}
+	if *result != expected {
+		t.Fatalf("Sync result was not as expected. expected=%v, actual=%v", expected, *result)
+	}
+
+	if len(source.requestedAfter) != 2 || source.requestedAfter[0] != 0 || source.requestedAfter[1] != 22 {
+		t.Fatalf("Expected summaries to be requested after 0 then 22 but was %v", source.requestedAfter)
+	}
+	if source.streamsRequested != 2 {
+		t.Fatalf("Expected 2 stream requests but got %d", source.streamsRequested)
+	}
+
+	efforts, err := store.Efforts()
+	if err != nil || len(efforts) != 1 || efforts[0].ActivityId != 11 {
+		t.Fatalf("Efforts were not as expected. efforts=%v, error=%s", efforts, err)
+	}
+}
+
+func TestSync_StreamsError(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	source := &testSource{
+		summaries: []*model.ActivitySummary{{Id: 11}, {Id: 22}, {Id: 33}},
+		noStreams: map[model.ActivityId]bool{22: true},
+	}
+
+	result, err := store.Sync(source, SyncOptions{Streams: true})
+	if err != nil {
+		t.Fatalf("Unexpected error for Sync. error=%s", err)
+	}
+	expected := SyncResult{Summaries: 3, Streams: 2, StreamsFailed: 1}
+	if *result != expected {
+		t.Fatalf("Sync result was not as expected. expected=%v, actual=%v", expected, *result)
+	}
+	if !store.HasStreams(11) || store.HasStreams(22) || !store.HasStreams(33) {
+		t.Fatalf("Expected streams of activities 11 and 33 only")
+	}
+
+	// The failed streams are retried by the next sync
+	delete(source.noStreams, 22)
+	result, err = store.Sync(source, SyncOptions{Streams: true})
+	if err != nil {
+		t.Fatalf("Unexpected error for Sync. error=%s", err)
+	}
+	expected = SyncResult{Streams: 1}
+	if *result != expected {
+		t.Fatalf("Sync result was not as expected. expected=%v, actual=%v", expected, *result)
+	}
+}
+
+func TestSync_GearError(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	source := &testSource{summaries: []*model.ActivitySummary{{Id: 11, GearId: "missing"}}}
+	if _, err := store.Sync(source, SyncOptions{}); err == nil {
+		t.Fatalf("Expected error for missing gear")
+	}
+}
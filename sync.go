@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alecholmes/strava/store"
+)
+
+// Fetches activities newer than those already in the local store, so other commands can
+// then run with --offline.
+func syncMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	storeFlag := flags.String("store", "", "directory of the local activity store; required")
+	streamsFlag := flags.Bool("streams", false, "also fetch streams, such as location and power, for every activity")
+	reconcileFlag := flags.Bool("reconcile", false, "compare all activities with Strava to find edits and deletions, printing what changed")
+	cacheFlag := addCacheFlag(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *accessTokenFlag == "" || *storeFlag == "" {
+		return usageErrorf("--accessToken and --store are required")
+	}
+
+	activityStore, err := store.Open(*storeFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	stravaClient, err := newClient(*accessTokenFlag, *cacheFlag)
+	if err != nil {
+		return fmt.Errorf("creating client: %s", err)
+	}
+	options := store.SyncOptions{Streams: *streamsFlag}
+
+	if *reconcileFlag {
+		diff, err := activityStore.Reconcile(stravaClient, options)
+		if err != nil {
+			return fmt.Errorf("reconciling: %s", err)
+		}
+		printDiff(diff)
+		return nil
+	}
+
+	result, err := activityStore.Sync(stravaClient, options)
+	if err != nil {
+		return fmt.Errorf("syncing: %s", err)
+	}
+
+	fmt.Printf("Synced %d new activities, %d activity details, %d streams and %d gear\n",
+		result.Summaries, result.Activities, result.Streams, result.Gear)
+	if result.StreamsFailed > 0 {
+		fmt.Fprintf(os.Stderr, "Streams of %d activities could not be fetched and will be retried by the next sync\n", result.StreamsFailed)
+	}
+	return nil
+}
+
+// Print one line per activity: + for added, ~ for changed and - for deleted.
+func printDiff(diff *store.Diff) {
+	if diff.Since.IsZero() {
+		fmt.Println("Changes since the store was created:")
+	} else {
+		fmt.Printf("Changes since last sync at %s:\n", diff.Since)
+	}
+
+	if diff.Empty() {
+		fmt.Println("No changes")
+		return
+	}
+
+	for _, summary := range diff.Added {
+		fmt.Printf("+ %d %s\n", summary.Id, summary.Name)
+	}
+	for _, change := range diff.Changed {
+		fields := make([]string, len(change.Fields))
+		for i, field := range change.Fields {
+			fields[i] = fmt.Sprintf("%s %q -> %q", field.Field, field.Old, field.New)
+		}
+		fmt.Printf("~ %d %s: %s\n", change.New.Id, change.New.Name, strings.Join(fields, ", "))
+	}
+	for _, summary := range diff.Deleted {
+		fmt.Printf("- %d %s\n", summary.Id, summary.Name)
+	}
+}
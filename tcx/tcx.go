@@ -0,0 +1,156 @@
+package tcx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/track"
+)
+
+// Elements are matched by local name, so the ActivityExtension namespace doesn't matter.
+type tcxFile struct {
+	Activities []struct {
+		Sport string   `xml:"Sport,attr"`
+		Laps  []tcxLap `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+type tcxLap struct {
+	StartTime        time.Time  `xml:"StartTime,attr"`
+	TotalTimeSeconds float32    `xml:"TotalTimeSeconds"`
+	DistanceMeters   float32    `xml:"DistanceMeters"`
+	MaximumSpeed     float32    `xml:"MaximumSpeed"`
+	AverageHeartrate float32    `xml:"AverageHeartRateBpm>Value"`
+	MaximumHeartrate float32    `xml:"MaximumHeartRateBpm>Value"`
+	Cadence          float32    `xml:"Cadence"`
+	AverageWatts     float32    `xml:"Extensions>LX>AvgWatts"`
+	Points           []tcxPoint `xml:"Track>Trackpoint"`
+}
+
+type tcxPoint struct {
+	Time       time.Time `xml:"Time"`
+	Lat        *float64  `xml:"Position>LatitudeDegrees"`
+	Lng        *float64  `xml:"Position>LongitudeDegrees"`
+	Altitude   *float32  `xml:"AltitudeMeters"`
+	Distance   *float32  `xml:"DistanceMeters"`
+	Heartrate  float32   `xml:"HeartRateBpm>Value"`
+	Cadence    float32   `xml:"Cadence"`
+	RunCadence float32   `xml:"Extensions>TPX>RunCadence"`
+	Watts      float32   `xml:"Extensions>TPX>Watts"`
+}
+
+// Strava activity types by TCX sport
+var sportTypes = map[string]string{
+	"Running": "Run",
+	"Biking":  "Ride",
+	"Other":   "Workout",
+}
+
+// A decoded TCX file.
+type File struct {
+	// Id is 0 since the activity isn't from Strava; Laps are included
+	Activity *model.Activity
+	Streams  *model.Streams
+}
+
+// Decode a TCX file. Laps of all activities in the file are combined into one activity,
+// typed after the first. See track.Build for how aggregates are computed.
+func Decode(r io.Reader) (*File, error) {
+	var file tcxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+
+	points := make([]*track.Point, 0)
+	laps := make([]*tcxLap, 0)
+	lapOf := make(map[*track.Point]int)
+	for _, tcxActivity := range file.Activities {
+		for i := range tcxActivity.Laps {
+			for _, p := range tcxActivity.Laps[i].Points {
+				point := toPoint(&p)
+				points = append(points, point)
+				lapOf[point] = len(laps)
+			}
+			laps = append(laps, &tcxActivity.Laps[i])
+		}
+	}
+
+	// Laps index into the streams, so only count the points that make it into them
+	points = track.Timed(points)
+	lapSizes := make([]int, len(laps))
+	for _, point := range points {
+		lapSizes[lapOf[point]]++
+	}
+
+	activity, streams := track.Build(points)
+	if len(file.Activities) > 0 {
+		activity.Type = sportTypes[file.Activities[0].Sport]
+	}
+
+	start := 0
+	for i, lap := range laps {
+		activity.Laps = append(activity.Laps, toLap(i+1, lap, streams, start, lapSizes[i]))
+		start += lapSizes[i]
+	}
+
+	return &File{Activity: activity, Streams: streams}, nil
+}
+
+func toPoint(p *tcxPoint) *track.Point {
+	point := &track.Point{
+		Time:      p.Time,
+		Heartrate: p.Heartrate,
+		Cadence:   p.Cadence,
+		Watts:     p.Watts,
+	}
+	if point.Cadence == 0 {
+		point.Cadence = p.RunCadence
+	}
+	if p.Lat != nil && p.Lng != nil {
+		point.Lat, point.Lng, point.HasLatLng = *p.Lat, *p.Lng, true
+	}
+	if p.Altitude != nil {
+		point.Altitude, point.HasAltitude = *p.Altitude, true
+	}
+	if p.Distance != nil {
+		point.Distance, point.HasDistance = *p.Distance, true
+	}
+	return point
+}
+
+// Convert a lap, whose size points start at the given index of the streams. Totals recorded by
+// the device are used, and moving time and elevation gain are computed from the lap's streams.
+func toLap(index int, lap *tcxLap, streams *model.Streams, start int, size int) *model.Lap {
+	result := &model.Lap{
+		Name:             fmt.Sprintf("Lap %d", index),
+		LapIndex:         uint32(index),
+		StartDate:        lap.StartTime.UTC(),
+		StartDateLocal:   lap.StartTime.UTC(),
+		ElapsedTime:      uint32(lap.TotalTimeSeconds + 0.5),
+		Distance:         lap.DistanceMeters,
+		MaxSpeed:         lap.MaximumSpeed,
+		AverageCadence:   lap.Cadence,
+		AverageWatts:     lap.AverageWatts,
+		AverageHeartrate: lap.AverageHeartrate,
+		MaxHeartrate:     lap.MaximumHeartrate,
+	}
+	if size == 0 {
+		return result
+	}
+
+	end := start + size - 1
+	result.StartIndex, result.EndIndex = uint32(start), uint32(end)
+
+	result.MovingTime = track.MovingTime(streams, start, end)
+	if len(streams.Altitude) > 0 {
+		result.TotalElevationGain = track.ElevationGain(streams.Altitude[start : end+1])
+	}
+	if result.MovingTime > 0 {
+		result.AverageSpeed = result.Distance / float32(result.MovingTime)
+	}
+
+	return result
+}
@@ -0,0 +1,119 @@
+package tcx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+const testTcx = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2"
+  xmlns:ns3="http://www.garmin.com/xmlschemas/ActivityExtension/v2">
+ <Activities>
+  <Activity Sport="Biking">
+   <Id>2018-06-01T21:00:00Z</Id>
+   <Lap StartTime="2018-06-01T21:00:00Z">
+    <TotalTimeSeconds>20.0</TotalTimeSeconds>
+    <DistanceMeters>200.0</DistanceMeters>
+    <MaximumSpeed>11.0</MaximumSpeed>
+    <AverageHeartRateBpm><Value>130</Value></AverageHeartRateBpm>
+    <MaximumHeartRateBpm><Value>140</Value></MaximumHeartRateBpm>
+    <Cadence>90</Cadence>
+    <Track>
+     <Trackpoint><Time>2018-06-01T21:00:00Z</Time><Position><LatitudeDegrees>37.0</LatitudeDegrees><LongitudeDegrees>-122.0</LongitudeDegrees></Position>
+      <AltitudeMeters>10.0</AltitudeMeters><DistanceMeters>0.0</DistanceMeters><HeartRateBpm><Value>120</Value></HeartRateBpm>
+      <Extensions><ns3:TPX><ns3:Watts>200</ns3:Watts></ns3:TPX></Extensions></Trackpoint>
+     <Trackpoint><Time>2018-06-01T21:00:10Z</Time><Position><LatitudeDegrees>37.0009</LatitudeDegrees><LongitudeDegrees>-122.0</LongitudeDegrees></Position>
+      <AltitudeMeters>11.0</AltitudeMeters><DistanceMeters>100.0</DistanceMeters><HeartRateBpm><Value>130</Value></HeartRateBpm>
+      <Extensions><ns3:TPX><ns3:Watts>220</ns3:Watts></ns3:TPX></Extensions></Trackpoint>
+     <Trackpoint><Time>2018-06-01T21:00:20Z</Time><Position><LatitudeDegrees>37.0018</LatitudeDegrees><LongitudeDegrees>-122.0</LongitudeDegrees></Position>
+      <AltitudeMeters>12.0</AltitudeMeters><DistanceMeters>200.0</DistanceMeters><HeartRateBpm><Value>140</Value></HeartRateBpm>
+      <Extensions><ns3:TPX><ns3:Watts>240</ns3:Watts></ns3:TPX></Extensions></Trackpoint>
+    </Track>
+    <Extensions><ns3:LX><ns3:AvgWatts>220</ns3:AvgWatts></ns3:LX></Extensions>
+   </Lap>
+   <Lap StartTime="2018-06-01T21:00:20Z">
+    <TotalTimeSeconds>60.0</TotalTimeSeconds>
+    <DistanceMeters>100.0</DistanceMeters>
+    <Track>
+     <Trackpoint><Time>2018-06-01T21:00:50Z</Time><AltitudeMeters>12.0</AltitudeMeters><DistanceMeters>200.0</DistanceMeters></Trackpoint>
+     <Trackpoint><Time>2018-06-01T21:01:20Z</Time><AltitudeMeters>12.0</AltitudeMeters><DistanceMeters>300.0</DistanceMeters></Trackpoint>
+    </Track>
+   </Lap>
+  </Activity>
+ </Activities>
+</TrainingCenterDatabase>`
+
+func TestDecode(t *testing.T) {
+	file, err := Decode(strings.NewReader(testTcx))
+	if err != nil {
+		t.Fatalf("Unexpected error for Decode. error=%s", err)
+	}
+
+	start := time.Date(2018, 6, 1, 21, 0, 0, 0, time.UTC)
+	activity := file.Activity
+	if activity.Type != "Ride" || !activity.StartDate.Equal(start) || activity.Distance != 300 {
+		t.Fatalf("Activity was not as expected. activity=%+v", activity)
+	}
+	// Stopped between 20 and 50 seconds
+	if activity.ElapsedTime != 80 || activity.MovingTime != 50 || activity.AverageSpeed != 6 || activity.MaxSpeed != 10 {
+		t.Fatalf("Activity times and speeds were not as expected. activity=%+v", activity)
+	}
+
+	expectedLaps := []*model.Lap{
+		{
+			Name:             "Lap 1",
+			LapIndex:         1,
+			StartDate:        start,
+			StartDateLocal:   start,
+			ElapsedTime:      20,
+			MovingTime:       20,
+			StartIndex:       0,
+			EndIndex:         2,
+			Distance:         200,
+			AverageSpeed:     10,
+			MaxSpeed:         11,
+			AverageCadence:   90,
+			AverageWatts:     220,
+			AverageHeartrate: 130,
+			MaxHeartrate:     140,
+		},
+		{
+			Name:           "Lap 2",
+			LapIndex:       2,
+			StartDate:      start.Add(20 * time.Second),
+			StartDateLocal: start.Add(20 * time.Second),
+			ElapsedTime:    60,
+			MovingTime:     30,
+			StartIndex:     3,
+			EndIndex:       4,
+			Distance:       100,
+			AverageSpeed:   float32(100) / 30,
+		},
+	}
+	if len(activity.Laps) != len(expectedLaps) {
+		t.Fatalf("Expected %d laps but got %d", len(expectedLaps), len(activity.Laps))
+	}
+	for i, lap := range activity.Laps {
+		if !reflect.DeepEqual(expectedLaps[i], lap) {
+			t.Fatalf("Lap was not as expected. expected=%+v, actual=%+v", expectedLaps[i], lap)
+		}
+	}
+
+	streams := file.Streams
+	if !reflect.DeepEqual(streams.Distance, []float32{0, 100, 200, 200, 300}) ||
+		!reflect.DeepEqual(streams.Watts, []float32{200, 220, 240, 0, 0}) ||
+		!reflect.DeepEqual(streams.Moving, []bool{false, true, true, false, true}) ||
+		streams.LatLng[4] != [2]float64{37.0018, -122.0} {
+		t.Fatalf("Streams were not as expected. streams=%+v", streams)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	if _, err := Decode(strings.NewReader("<TrainingCenterDatabase><Activities>")); err == nil {
+		t.Fatalf("Expected an error for a truncated file")
+	}
+}
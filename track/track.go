@@ -0,0 +1,276 @@
+package track
+
+import (
+	"math"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+const earthRadius = 6371000.0 // Meters
+
+const (
+	// Slower than this between two points is stopped, and not counted in moving time
+	movingSpeed = 0.5 // Meters/sec
+
+	// Number of points speed and grade are smoothed over
+	smoothingWindow = 5
+
+	// Climbs smaller than this are treated as noise and don't count toward elevation gain
+	elevationThreshold = 2.0 // Meters
+)
+
+// A recorded sample. Values that weren't recorded are left as zero; Has* distinguish
+// values where zero is meaningful.
+type Point struct {
+	Time        time.Time
+	Lat         float64
+	Lng         float64
+	HasLatLng   bool
+	Altitude    float32 // Meters
+	HasAltitude bool
+	Distance    float32 // Meters since start
+	HasDistance bool
+	Heartrate   float32
+	Cadence     float32
+	Watts       float32
+	Temp        float32 // Degrees Celsius
+	HasTemp     bool
+}
+
+// Build an activity and its streams from points, in the order they were recorded.
+//
+// Distance is taken from the points when they all have it, and otherwise is the great circle
+// distance between positions. Moving time only counts time between points at least
+// movingSpeed apart. Elevation gain is from smoothed altitude, ignoring climbs smaller than
+// elevationThreshold. Start dates are in UTC, since track files don't record a timezone.
+// Points are filtered with Timed first.
+func Build(points []*Point) (*model.Activity, *model.Streams) {
+	points = Timed(points)
+	activity := &model.Activity{}
+	streams := Streams(points)
+	if len(points) == 0 {
+		return activity, streams
+	}
+
+	activity.StartDate = points[0].Time.UTC()
+	activity.StartDateLocal = activity.StartDate
+	activity.ElapsedTime = streams.Time[len(streams.Time)-1]
+	if len(streams.Distance) > 0 {
+		activity.Distance = streams.Distance[len(streams.Distance)-1]
+	}
+	activity.MovingTime = MovingTime(streams, 0, len(points)-1)
+	activity.TotalElevationGain = ElevationGain(streams.Altitude)
+	for _, speed := range streams.VelocitySmooth {
+		activity.MaxSpeed = float32(math.Max(float64(activity.MaxSpeed), float64(speed)))
+	}
+	if activity.MovingTime > 0 {
+		activity.AverageSpeed = activity.Distance / float32(activity.MovingTime)
+	}
+
+	return activity, streams
+}
+
+// The points that have a time no earlier than the point kept before them, in order.
+// Streams count seconds since the first point, so untimed points and points recorded out of
+// order can't be placed in them.
+func Timed(points []*Point) []*Point {
+	timed := make([]*Point, 0, len(points))
+	for _, p := range points {
+		if p.Time.IsZero() || (len(timed) > 0 && p.Time.Before(timed[len(timed)-1].Time)) {
+			continue
+		}
+		timed = append(timed, p)
+	}
+	return timed
+}
+
+// Convert points to streams. Streams no point has a value for are left empty. Positions
+// carry forward over points without them. Points are filtered with Timed first.
+func Streams(points []*Point) *model.Streams {
+	points = Timed(points)
+	streams := &model.Streams{}
+	if len(points) == 0 {
+		return streams
+	}
+
+	allDistance, hasLatLng, hasAltitude, hasHeartrate, hasCadence, hasWatts, hasTemp := true, false, false, false, false, false, false
+	var latLng [2]float64
+	for _, p := range points {
+		allDistance = allDistance && p.HasDistance
+		hasAltitude = hasAltitude || p.HasAltitude
+		hasHeartrate = hasHeartrate || p.Heartrate > 0
+		hasCadence = hasCadence || p.Cadence > 0
+		hasWatts = hasWatts || p.Watts > 0
+		hasTemp = hasTemp || p.HasTemp
+		if p.HasLatLng && !hasLatLng {
+			hasLatLng = true
+			latLng = [2]float64{p.Lat, p.Lng}
+		}
+	}
+
+	start := points[0].Time
+	var distance float64
+	var altitude float32
+	for i, p := range points {
+		streams.Time = append(streams.Time, uint32(p.Time.Sub(start)/time.Second))
+
+		if hasLatLng {
+			previous := latLng
+			if p.HasLatLng {
+				latLng = [2]float64{p.Lat, p.Lng}
+			}
+			if i > 0 && !allDistance {
+				distance += haversine(previous, latLng)
+			}
+			streams.LatLng = append(streams.LatLng, latLng)
+		}
+		if allDistance {
+			streams.Distance = append(streams.Distance, p.Distance)
+		} else if hasLatLng {
+			streams.Distance = append(streams.Distance, float32(distance))
+		}
+
+		if hasAltitude {
+			if p.HasAltitude {
+				altitude = p.Altitude
+			}
+			streams.Altitude = append(streams.Altitude, altitude)
+		}
+		if hasHeartrate {
+			streams.Heartrate = append(streams.Heartrate, p.Heartrate)
+		}
+		if hasCadence {
+			streams.Cadence = append(streams.Cadence, p.Cadence)
+		}
+		if hasWatts {
+			streams.Watts = append(streams.Watts, p.Watts)
+		}
+		if hasTemp {
+			streams.Temp = append(streams.Temp, p.Temp)
+		}
+	}
+
+	if len(streams.Distance) > 0 {
+		streams.VelocitySmooth = velocity(streams.Time, streams.Distance)
+		streams.Moving = moving(streams.Time, streams.Distance)
+		if len(streams.Altitude) > 0 {
+			streams.GradeSmooth = grade(streams.Distance, streams.Altitude)
+		}
+	}
+
+	return streams
+}
+
+// Seconds spent moving between the points at indexes start and end, inclusive.
+// Without a moving stream, all of the time counts. Time going backwards doesn't count.
+func MovingTime(streams *model.Streams, start int, end int) uint32 {
+	if len(streams.Moving) == 0 {
+		if streams.Time[end] < streams.Time[start] {
+			return 0
+		}
+		return streams.Time[end] - streams.Time[start]
+	}
+
+	var movingTime uint32
+	for i := start + 1; i <= end; i++ {
+		if streams.Moving[i] && streams.Time[i] > streams.Time[i-1] {
+			movingTime += streams.Time[i] - streams.Time[i-1]
+		}
+	}
+	return movingTime
+}
+
+// Total climbing of an altitude stream, after smoothing it.
+func ElevationGain(altitude []float32) float32 {
+	smoothed := smooth(altitude)
+
+	var gain float32
+	for i := 0; i < len(smoothed); {
+		// Climb from the current low point until the next drop
+		low := smoothed[i]
+		j := i + 1
+		for j < len(smoothed) && smoothed[j] >= smoothed[j-1] {
+			j++
+		}
+		if climb := smoothed[j-1] - low; climb >= elevationThreshold {
+			gain += climb
+		}
+		i = j
+	}
+	return gain
+}
+
+// Speed at each point over the preceding smoothingWindow points.
+func velocity(times []uint32, distance []float32) []float32 {
+	speeds := make([]float32, len(times))
+	for i := 1; i < len(times); i++ {
+		j := i - smoothingWindow
+		if j < 0 {
+			j = 0
+		}
+		if times[i] > times[j] {
+			speeds[i] = (distance[i] - distance[j]) / float32(times[i]-times[j])
+		}
+	}
+	return speeds
+}
+
+// Whether the athlete was moving between each point and the previous one.
+func moving(times []uint32, distance []float32) []bool {
+	moving := make([]bool, len(times))
+	for i := 1; i < len(times); i++ {
+		moving[i] = times[i] > times[i-1] && (distance[i]-distance[i-1])/float32(times[i]-times[i-1]) >= movingSpeed
+	}
+	return moving
+}
+
+// Percent grade at each point over the surrounding smoothingWindow points.
+func grade(distance []float32, altitude []float32) []float32 {
+	grades := make([]float32, len(distance))
+	for i := range distance {
+		j, k := i-smoothingWindow/2, i+smoothingWindow/2
+		if j < 0 {
+			j = 0
+		}
+		if k >= len(distance) {
+			k = len(distance) - 1
+		}
+		if run := distance[k] - distance[j]; run >= 1 {
+			grades[i] = 100 * (altitude[k] - altitude[j]) / run
+		}
+	}
+	return grades
+}
+
+// Centered moving average over smoothingWindow points.
+func smooth(values []float32) []float32 {
+	smoothed := make([]float32, len(values))
+	for i := range values {
+		j, k := i-smoothingWindow/2, i+smoothingWindow/2
+		if j < 0 {
+			j = 0
+		}
+		if k >= len(values) {
+			k = len(values) - 1
+		}
+
+		var sum float32
+		for _, value := range values[j : k+1] {
+			sum += value
+		}
+		smoothed[i] = sum / float32(k-j+1)
+	}
+	return smoothed
+}
+
+// Great circle distance in meters between two [latitude, longitude] points.
+func haversine(from [2]float64, to [2]float64) float64 {
+	toRadians := math.Pi / 180
+	dLat := (to[0] - from[0]) * toRadians
+	dLng := (to[1] - from[1]) * toRadians
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(from[0]*toRadians)*math.Cos(to[0]*toRadians)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,113 @@
+package track
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+var testStart = time.Date(2018, 6, 1, 14, 0, 0, 0, time.FixedZone("PDT", -7*3600))
+
+func TestBuild(t *testing.T) {
+	distances := []float32{0, 50, 100, 100, 100, 150}
+	altitudes := []float32{100, 100, 100, 110, 120, 130}
+	points := make([]*Point, len(distances))
+	for i := range distances {
+		points[i] = &Point{
+			Time:        testStart.Add(time.Duration(10*i) * time.Second),
+			Distance:    distances[i],
+			HasDistance: true,
+			Altitude:    altitudes[i],
+			HasAltitude: true,
+			Heartrate:   float32(120 + i),
+		}
+	}
+
+	activity, streams := Build(points)
+
+	if !activity.StartDate.Equal(testStart) || activity.StartDate.Location() != time.UTC || activity.StartDateLocal != activity.StartDate {
+		t.Fatalf("Start dates were not as expected. activity=%+v", activity)
+	}
+	// Stopped between 20 and 40 seconds
+	if activity.ElapsedTime != 50 || activity.MovingTime != 30 {
+		t.Fatalf("Times were not as expected. elapsed=%d, moving=%d", activity.ElapsedTime, activity.MovingTime)
+	}
+	if activity.Distance != 150 || activity.AverageSpeed != 5 || activity.MaxSpeed != 5 {
+		t.Fatalf("Distance and speeds were not as expected. activity=%+v", activity)
+	}
+	if expected := []bool{false, true, true, false, false, true}; !reflect.DeepEqual(expected, streams.Moving) {
+		t.Fatalf("Moving stream was not as expected. expected=%v, actual=%v", expected, streams.Moving)
+	}
+	if expected := []uint32{0, 10, 20, 30, 40, 50}; !reflect.DeepEqual(expected, streams.Time) {
+		t.Fatalf("Time stream was not as expected. expected=%v, actual=%v", expected, streams.Time)
+	}
+	if len(streams.LatLng) != 0 || len(streams.Cadence) != 0 || len(streams.Heartrate) != 6 || len(streams.GradeSmooth) != 6 {
+		t.Fatalf("Streams were not as expected. streams=%+v", streams)
+	}
+}
+
+func TestBuild_DistanceFromPositions(t *testing.T) {
+	points := []*Point{
+		{Time: testStart, Lat: 37, Lng: -122, HasLatLng: true},
+		{Time: testStart.Add(10 * time.Second)},
+		{Time: testStart.Add(20 * time.Second), Lat: 37.001, Lng: -122, HasLatLng: true},
+	}
+
+	activity, streams := Build(points)
+
+	// A point without a position stays where the last one was
+	if expected := [][2]float64{{37, -122}, {37, -122}, {37.001, -122}}; !reflect.DeepEqual(expected, streams.LatLng) {
+		t.Fatalf("LatLng stream was not as expected. expected=%v, actual=%v", expected, streams.LatLng)
+	}
+	// 0.001 degrees of latitude is about 111 meters
+	if activity.Distance < 110 || activity.Distance > 112 || streams.Distance[1] != 0 {
+		t.Fatalf("Expected a distance of about 111m. activity=%+v, distance=%v", activity, streams.Distance)
+	}
+	if activity.MovingTime != 10 {
+		t.Fatalf("Expected 10 seconds of moving time but got %d", activity.MovingTime)
+	}
+}
+
+func TestBuild_Empty(t *testing.T) {
+	activity, streams := Build(nil)
+	if activity.ElapsedTime != 0 || streams.Len() != 0 {
+		t.Fatalf("Expected an empty activity. activity=%+v, streams=%+v", activity, streams)
+	}
+}
+
+func TestMovingTime_Backwards(t *testing.T) {
+	// Streams from elsewhere may not be filtered by Timed
+	streams := &model.Streams{Time: []uint32{10, 20, 5, 30}}
+	if movingTime := MovingTime(streams, 1, 2); movingTime != 0 {
+		t.Fatalf("Expected no moving time but got %d", movingTime)
+	}
+
+	streams.Moving = []bool{false, true, true, true}
+	if movingTime := MovingTime(streams, 0, 3); movingTime != 35 {
+		t.Fatalf("Expected 35 seconds of moving time but got %d", movingTime)
+	}
+	if expected := []bool{false, true, false, true}; !reflect.DeepEqual(expected, moving(streams.Time, []float32{0, 100, 200, 300})) {
+		t.Fatalf("Moving stream was not as expected. expected=%v", expected)
+	}
+}
+
+func TestElevationGain(t *testing.T) {
+	cases := []struct {
+		altitude []float32
+		expected float32
+	}{
+		{[]float32{100, 100, 100, 110, 120, 130, 130, 130}, 30},
+		// Noise is smoothed away
+		{[]float32{100, 101, 100, 101, 100, 101, 100}, 0},
+		{[]float32{100, 100, 100, 120, 120, 120, 100, 100, 100, 120, 120, 120}, 24},
+		{[]float32{}, 0},
+	}
+
+	for _, c := range cases {
+		if gain := ElevationGain(c.altitude); gain != c.expected {
+			t.Fatalf("Elevation gain was not as expected. altitude=%v, expected=%f, actual=%f", c.altitude, c.expected, gain)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecholmes/strava/model"
+)
+
+const (
+	metersPerKilometer = 1000.0
+	metersPerMile      = 1609.344
+	metersPerFoot      = 0.3048
+)
+
+// A system of units to show measurements in. Strava measurements are always in
+// meters, meters/sec and seconds.
+type System string
+
+const (
+	Metric   = System("metric")
+	Imperial = System("imperial")
+)
+
+// Measurement preferences of athletes
+const (
+	Meters = "meters"
+	Feet   = "feet"
+)
+
+func ParseSystem(s string) (System, error) {
+	switch System(strings.ToLower(s)) {
+	case Metric:
+		return Metric, nil
+	case Imperial:
+		return Imperial, nil
+	}
+	return "", fmt.Errorf("unknown unit system %s; must be metric or imperial", s)
+}
+
+// The system an athlete prefers. Metric unless they prefer feet.
+func ForAthlete(athlete *model.Athlete) System {
+	if athlete != nil && athlete.MeasurementPreference == Feet {
+		return Imperial
+	}
+	return Metric
+}
+
+// Whether activities of a type are usually measured by pace rather than speed.
+func UsesPace(activityType string) bool {
+	switch activityType {
+	case "Run", "VirtualRun", "Walk", "Hike", "Swim":
+		return true
+	}
+	return false
+}
+
+// Kilometers or miles.
+func (s System) Distance(meters float64) float64 {
+	return meters / s.metersPerDistance()
+}
+
+func (s System) DistanceUnit() string {
+	if s == Imperial {
+		return "mi"
+	}
+	return "km"
+}
+
+// Meters or feet.
+func (s System) Elevation(meters float64) float64 {
+	if s == Imperial {
+		return meters / metersPerFoot
+	}
+	return meters
+}
+
+func (s System) ElevationUnit() string {
+	if s == Imperial {
+		return "ft"
+	}
+	return "m"
+}
+
+// Kilometers or miles per hour.
+func (s System) Speed(metersPerSecond float64) float64 {
+	return s.Distance(metersPerSecond * 3600)
+}
+
+func (s System) SpeedUnit() string {
+	if s == Imperial {
+		return "mph"
+	}
+	return "km/h"
+}
+
+// Seconds per kilometer or mile. Returns 0 when not moving.
+func (s System) Pace(metersPerSecond float64) float64 {
+	if metersPerSecond <= 0 {
+		return 0
+	}
+	return s.metersPerDistance() / metersPerSecond
+}
+
+func (s System) PaceUnit() string {
+	return "/" + s.DistanceUnit()
+}
+
+func (s System) FormatDistance(meters float64) string {
+	return fmt.Sprintf("%.2f %s", s.Distance(meters), s.DistanceUnit())
+}
+
+func (s System) FormatElevation(meters float64) string {
+	return fmt.Sprintf("%.0f %s", s.Elevation(meters), s.ElevationUnit())
+}
+
+func (s System) FormatSpeed(metersPerSecond float64) string {
+	return fmt.Sprintf("%.1f %s", s.Speed(metersPerSecond), s.SpeedUnit())
+}
+
+// Pace as m:ss per kilometer or mile, or an empty string when not moving.
+func (s System) FormatPace(metersPerSecond float64) string {
+	pace := s.Pace(metersPerSecond)
+	if pace == 0 {
+		return ""
+	}
+
+	seconds := uint64(pace + 0.5)
+	return fmt.Sprintf("%d:%02d %s", seconds/60, seconds%60, s.PaceUnit())
+}
+
+// Seconds as h:mm:ss.
+func FormatDuration(seconds uint64) string {
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+func (s System) metersPerDistance() float64 {
+	if s == Imperial {
+		return metersPerMile
+	}
+	return metersPerKilometer
+}
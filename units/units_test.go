@@ -0,0 +1,79 @@
+package units
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alecholmes/strava/model"
+)
+
+func TestParseSystem(t *testing.T) {
+	if system, err := ParseSystem("Imperial"); system != Imperial || err != nil {
+		t.Fatalf("Expected imperial. system=%s, error=%v", system, err)
+	}
+	if _, err := ParseSystem("furlongs"); err == nil {
+		t.Fatalf("Expected an error for an unknown system")
+	}
+}
+
+func TestForAthlete(t *testing.T) {
+	if system := ForAthlete(&model.Athlete{MeasurementPreference: Feet}); system != Imperial {
+		t.Fatalf("Expected imperial for feet but got %s", system)
+	}
+	if system := ForAthlete(&model.Athlete{MeasurementPreference: Meters}); system != Metric {
+		t.Fatalf("Expected metric for meters but got %s", system)
+	}
+	if system := ForAthlete(nil); system != Metric {
+		t.Fatalf("Expected metric without an athlete but got %s", system)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	cases := []struct {
+		name     string
+		actual   float64
+		expected float64
+	}{
+		{"metric distance", Metric.Distance(1500), 1.5},
+		{"imperial distance", Imperial.Distance(1609.344), 1},
+		{"metric elevation", Metric.Elevation(100), 100},
+		{"imperial elevation", Imperial.Elevation(100), 328.084},
+		{"metric speed", Metric.Speed(10), 36},
+		{"imperial speed", Imperial.Speed(10), 22.3694},
+		{"metric pace", Metric.Pace(4), 250},
+		{"imperial pace", Imperial.Pace(4), 402.336},
+		{"stopped pace", Metric.Pace(0), 0},
+	}
+
+	for _, c := range cases {
+		if math.Abs(c.actual-c.expected) > 0.001 {
+			t.Fatalf("Conversion was not as expected for %s. expected=%f, actual=%f", c.name, c.expected, c.actual)
+		}
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	cases := []struct {
+		actual   string
+		expected string
+	}{
+		{Metric.FormatDistance(42195), "42.20 km"},
+		{Imperial.FormatDistance(42195), "26.22 mi"},
+		{Metric.FormatElevation(1234.4), "1234 m"},
+		{Imperial.FormatElevation(1000), "3281 ft"},
+		{Metric.FormatSpeed(8.5), "30.6 km/h"},
+		{Imperial.FormatSpeed(8.5), "19.0 mph"},
+		{Metric.FormatPace(3.5), "4:46 /km"},
+		{Imperial.FormatPace(3.5), "7:40 /mi"},
+		{Metric.FormatPace(0), ""},
+		{FormatDuration(59), "0:00:59"},
+		{FormatDuration(3723), "1:02:03"},
+		{FormatDuration(36000), "10:00:00"},
+	}
+
+	for _, c := range cases {
+		if c.actual != c.expected {
+			t.Fatalf("Formatting was not as expected. expected=%s, actual=%s", c.expected, c.actual)
+		}
+	}
+}
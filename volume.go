@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/alecholmes/strava/analysis"
+	"github.com/alecholmes/strava/model"
+	"github.com/alecholmes/strava/units"
+)
+
+// Prints distance, time and elevation totals per week, month or year.
+func volumeReportMain(flags *flag.FlagSet, args []string) error {
+	accessTokenFlag := addAccessTokenFlag(flags)
+	afterFlag := flags.Int("afterId", 0, "beginning activity id, exclusive")
+	byFlag := flags.String("by", string(analysis.Week), "period to total by: week, month or year")
+	typeFlag := flags.String("type", "", "comma separated activity types to include, such as Ride,Run; all types if not set")
+	storeFlags := addStoreFlags(flags)
+	outputFlags := addOutputFlags(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	period, err := analysis.ParsePeriod(*byFlag)
+	if err != nil {
+		return usageErrorf("--by must be week, month or year")
+	}
+	if *accessTokenFlag == "" && storeFlags.needsAccessToken() {
+		return usageErrorf("--accessToken is required unless --offline")
+	}
+	if err := outputFlags.setup(*accessTokenFlag, *storeFlags.cacheDir); err != nil {
+		return err
+	}
+
+	source, err := storeFlags.source(*accessTokenFlag)
+	if err != nil {
+		return fmt.Errorf("opening store: %s", err)
+	}
+
+	summaries, err := source.GetActivitySummaries(model.ActivityId(*afterFlag))
+	if err != nil {
+		return fmt.Errorf("getting activity summaries: %s", err)
+	}
+
+	var types []string
+	if *typeFlag != "" {
+		types = strings.Split(*typeFlag, ",")
+	}
+
+	return outputFlags.print(volumeTable(analysis.VolumeBy(summaries, period, types), types))
+}
+
+// Average speed is shown as pace when only activity types measured by pace are included.
+func volumeTable(volumes []*analysis.Volume, types []string) *table {
+	usesPace := len(types) > 0
+	for _, activityType := range types {
+		usesPace = usesPace && units.UsesPace(activityType)
+	}
+
+	t := newTable("period", "start", "count", "distance", "moving_time", "elevation_gain", "average_speed")
+	for _, volume := range volumes {
+		var average interface{} = speed(volume.AverageSpeed())
+		if usesPace {
+			average = pace(volume.AverageSpeed())
+		}
+		t.add(volume.Label(), volume.Start.Format(dateLayout), volume.Count, distance(volume.Distance),
+			duration(volume.MovingTime), elevation(volume.ElevationGain), average)
+	}
+	return t
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/alecholmes/strava/model"
+)
+
+type ObjectType string
+type AspectType string
+
+const (
+	ActivityObject = ObjectType("activity")
+	AthleteObject  = ObjectType("athlete")
+)
+
+const (
+	Create = AspectType("create")
+	Update = AspectType("update")
+	Delete = AspectType("delete")
+)
+
+// A change to an activity or athlete, pushed by Strava to a subscription's callback url.
+type Event struct {
+	ObjectType     ObjectType
+	ObjectId       int64 // Activity or athlete id, depending on ObjectType
+	AspectType     AspectType
+	Updates        map[string]string // Changed fields for updates, e.g. title or type
+	OwnerId        model.AthleteId
+	SubscriptionId SubscriptionId
+	EventTime      time.Time
+}
+
+// The event as sent by Strava. Times are in seconds since epoch.
+type eventJson struct {
+	ObjectType     ObjectType        `json:"object_type"`
+	ObjectId       int64             `json:"object_id"`
+	AspectType     AspectType        `json:"aspect_type"`
+	Updates        map[string]string `json:"updates"`
+	OwnerId        model.AthleteId   `json:"owner_id"`
+	SubscriptionId SubscriptionId    `json:"subscription_id"`
+	EventTime      int64             `json:"event_time"`
+}
+
+func decodeEvent(body []byte) (*Event, error) {
+	var raw eventJson
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		ObjectType:     raw.ObjectType,
+		ObjectId:       raw.ObjectId,
+		AspectType:     raw.AspectType,
+		Updates:        raw.Updates,
+		OwnerId:        raw.OwnerId,
+		SubscriptionId: raw.SubscriptionId,
+		EventTime:      time.Unix(raw.EventTime, 0).UTC(),
+	}, nil
+}
+
+// The id of the activity the event is for. Only meaningful if ObjectType is ActivityObject.
+func (e *Event) ActivityId() model.ActivityId {
+	return model.ActivityId(e.ObjectId)
+}
+
+// Whether the athlete revoked the application's access, after which no more events are sent for them.
+func (e *Event) IsDeauthorization() bool {
+	return e.ObjectType == AthleteObject && e.AspectType == Update && e.Updates["authorized"] == "false"
+}
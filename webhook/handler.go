@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// Receives events delivered to a subscription's callback url.
+// Strava expects a response within two seconds, so slow work should be done asynchronously.
+type EventHandler interface {
+	HandleEvent(event *Event)
+}
+
+// Adapts a function to an EventHandler.
+type EventHandlerFunc func(event *Event)
+
+func (f EventHandlerFunc) HandleEvent(event *Event) {
+	f(event)
+}
+
+type handler struct {
+	verifyToken  string
+	eventHandler EventHandler
+}
+
+// Create an http.Handler to mount at a subscription's callback url. It answers the subscription
+// validation request for the given verifyToken and passes received events to eventHandler.
+func NewHandler(verifyToken string, eventHandler EventHandler) http.Handler {
+	return &handler{verifyToken: verifyToken, eventHandler: eventHandler}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		h.serveChallenge(w, r)
+	case "POST":
+		h.serveEvent(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Echo hub.challenge back to Strava when it validates a new subscription.
+func (h *handler) serveChallenge(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("hub.mode") != "subscribe" {
+		http.Error(w, "unexpected hub.mode", http.StatusBadRequest)
+		return
+	}
+	if query.Get("hub.verify_token") != h.verifyToken {
+		http.Error(w, "invalid hub.verify_token", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"hub.challenge": query.Get("hub.challenge")})
+}
+
+func (h *handler) serveEvent(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "could not read event", http.StatusBadRequest)
+		return
+	}
+
+	event, err := decodeEvent(body)
+	if err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	h.eventHandler.HandleEvent(event)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_Challenge(t *testing.T) {
+	handler := NewHandler("secret", EventHandlerFunc(func(event *Event) {
+		t.Fatalf("Unexpected event %v", event)
+	}))
+
+	request := httptest.NewRequest("GET", "/callback?hub.mode=subscribe&hub.challenge=15f7d1a91c1f40f8a748fd134752feb3&hub.verify_token=secret", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected status %d", recorder.Code)
+	}
+
+	var response map[string]string
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not unmarshal response. error=%s", err)
+	}
+	if response["hub.challenge"] != "15f7d1a91c1f40f8a748fd134752feb3" {
+		t.Fatalf("Challenge was not echoed. response=%v", response)
+	}
+}
+
+func TestHandler_ChallengeWrongToken(t *testing.T) {
+	handler := NewHandler("secret", EventHandlerFunc(func(event *Event) {}))
+
+	request := httptest.NewRequest("GET", "/callback?hub.mode=subscribe&hub.challenge=abc&hub.verify_token=wrong", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("Expected forbidden but got %d", recorder.Code)
+	}
+}
+
+func TestHandler_Event(t *testing.T) {
+	var received *Event
+	handler := NewHandler("secret", EventHandlerFunc(func(event *Event) {
+		received = event
+	}))
+
+	body := `{
+		"aspect_type": "update",
+		"event_time": 1516126040,
+		"object_id": 1360128428,
+		"object_type": "activity",
+		"owner_id": 134815,
+		"subscription_id": 120475,
+		"updates": {"title": "Messy", "type": "Run"}
+	}`
+	request := httptest.NewRequest("POST", "/callback", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected status %d", recorder.Code)
+	}
+
+	expected := Event{
+		ObjectType:     ActivityObject,
+		ObjectId:       1360128428,
+		AspectType:     Update,
+		Updates:        map[string]string{"title": "Messy", "type": "Run"},
+		OwnerId:        134815,
+		SubscriptionId: 120475,
+		EventTime:      time.Date(2018, 1, 16, 18, 7, 20, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(&expected, received) {
+		t.Fatalf("Events were not the same. expected=%v, actual=%v", &expected, received)
+	}
+	if received.ActivityId() != 1360128428 {
+		t.Fatalf("Unexpected activity id %d", received.ActivityId())
+	}
+	if received.IsDeauthorization() {
+		t.Fatalf("Activity update should not be a deauthorization")
+	}
+}
+
+func TestHandler_Deauthorization(t *testing.T) {
+	var received *Event
+	handler := NewHandler("secret", EventHandlerFunc(func(event *Event) {
+		received = event
+	}))
+
+	body := `{"aspect_type": "update", "event_time": 1516126040, "object_id": 134815, "object_type": "athlete",
+		"owner_id": 134815, "subscription_id": 120475, "updates": {"authorized": "false"}}`
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/callback", strings.NewReader(body)))
+
+	if received == nil || !received.IsDeauthorization() {
+		t.Fatalf("Expected deauthorization event but got %v", received)
+	}
+}
+
+func TestHandler_InvalidEvent(t *testing.T) {
+	handler := NewHandler("secret", EventHandlerFunc(func(event *Event) {
+		t.Fatalf("Unexpected event %v", event)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/callback", strings.NewReader("not json")))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected bad request but got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const stravaBaseUrl = "https://www.strava.com/api/v3"
+
+const subscriptionsUrl = "/push_subscriptions"
+
+type SubscriptionId int64
+
+type Subscription struct {
+	Id            SubscriptionId `json:"id"`
+	ApplicationId int64          `json:"application_id"`
+	CallbackUrl   string         `json:"callback_url"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// Creates, lists and deletes an application's push subscriptions.
+// Subscriptions are authenticated by the application's client id and secret rather than
+// an athlete's access token.
+type SubscriptionManager struct {
+	clientId     string
+	clientSecret string
+	baseUrl      string
+	httpClient   *http.Client
+}
+
+func NewSubscriptionManager(clientId string, clientSecret string) *SubscriptionManager {
+	return &SubscriptionManager{clientId: clientId, clientSecret: clientSecret, baseUrl: stravaBaseUrl, httpClient: http.DefaultClient}
+}
+
+// Create a subscription. Before this returns, Strava validates the callback url by sending it
+// verifyToken, which a Handler created with the same token answers.
+// An application may only have one subscription.
+func (m *SubscriptionManager) Create(callbackUrl string, verifyToken string) (*Subscription, error) {
+	form := m.credentials()
+	form.Set("callback_url", callbackUrl)
+	form.Set("verify_token", verifyToken)
+
+	request, _ := http.NewRequest("POST", m.baseUrl+subscriptionsUrl, strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := m.do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscription Subscription
+	if err := json.Unmarshal(body, &subscription); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (m *SubscriptionManager) List() ([]*Subscription, error) {
+	request, _ := http.NewRequest("GET", m.baseUrl+subscriptionsUrl+"?"+m.credentials().Encode(), nil)
+
+	body, err := m.do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*Subscription, 0)
+	if err := json.Unmarshal(body, &subscriptions); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (m *SubscriptionManager) Delete(subscriptionId SubscriptionId) error {
+	deleteUrl := fmt.Sprintf("%s%s/%d?%s", m.baseUrl, subscriptionsUrl, subscriptionId, m.credentials().Encode())
+	request, _ := http.NewRequest("DELETE", deleteUrl, nil)
+
+	_, err := m.do(request)
+	return err
+}
+
+func (m *SubscriptionManager) credentials() url.Values {
+	return url.Values{"client_id": {m.clientId}, "client_secret": {m.clientSecret}}
+}
+
+func (m *SubscriptionManager) do(request *http.Request) ([]byte, error) {
+	request.Header.Set("Accept", "application/json")
+
+	response, err := m.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("Unexpected HTTP response %v: %s", response.Status, body)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSubscriptionManager_Create(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Method != "POST" || r.URL.Path != subscriptionsUrl {
+			t.Errorf("Unexpected request. method=%s, path=%s", r.Method, r.URL.Path)
+		}
+		if r.PostForm.Get("client_id") != "5" || r.PostForm.Get("client_secret") != "shh" ||
+			r.PostForm.Get("callback_url") != "http://example.com/callback" || r.PostForm.Get("verify_token") != "token" {
+			t.Errorf("Unexpected form %v", r.PostForm)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 120475}`))
+	}))
+	defer server.Close()
+
+	subscription, err := testManager(server).Create("http://example.com/callback", "token")
+	if err != nil {
+		t.Fatalf("Unexpected error for Create. error=%s", err)
+	}
+	if subscription.Id != 120475 {
+		t.Fatalf("Unexpected subscription id %d", subscription.Id)
+	}
+}
+
+func TestSubscriptionManager_List(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.Method != "GET" || query.Get("client_id") != "5" || query.Get("client_secret") != "shh" {
+			t.Errorf("Unexpected request. method=%s, query=%v", r.Method, query)
+		}
+		w.Write([]byte(`[{"id": 1, "application_id": 5, "callback_url": "http://example.com/callback",
+			"created_at": "2018-01-16T18:07:20Z", "updated_at": "2018-01-16T18:07:20Z"}]`))
+	}))
+	defer server.Close()
+
+	subscriptions, err := testManager(server).List()
+	if err != nil {
+		t.Fatalf("Unexpected error for List. error=%s", err)
+	}
+	if len(subscriptions) != 1 || subscriptions[0].Id != 1 || subscriptions[0].CallbackUrl != "http://example.com/callback" {
+		t.Fatalf("Unexpected subscriptions %v", subscriptions)
+	}
+}
+
+func TestSubscriptionManager_Delete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != subscriptionsUrl+"/1" || r.URL.Query().Get("client_id") != "5" {
+			t.Errorf("Unexpected request. method=%s, url=%s", r.Method, r.URL)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := testManager(server).Delete(SubscriptionId(1)); err != nil {
+		t.Fatalf("Unexpected error for Delete. error=%s", err)
+	}
+}
+
+func TestSubscriptionManager_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Bad Request"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	if _, err := testManager(server).List(); err == nil {
+		t.Fatalf("Expected error for bad request")
+	}
+}
+
+func testManager(server *httptest.Server) *SubscriptionManager {
+	manager := NewSubscriptionManager("5", "shh")
+	manager.baseUrl = server.URL
+	return manager
+}